@@ -0,0 +1,79 @@
+package csv
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// An AnonymizeStrategy replaces a value holding personal data, as used by
+// Column.Anonymize and Reader.AddAnonymizer.
+type AnonymizeStrategy interface {
+	// AnonymizeValue returns the replacement for value.
+	AnonymizeValue(value string) string
+}
+
+// An AnonymizeFunc is a function used as an AnonymizeStrategy.
+type AnonymizeFunc func(value string) string
+
+// AnonymizeValue returns f(value).
+func (f AnonymizeFunc) AnonymizeValue(value string) string { return f(value) }
+
+// These are the AnonymizeStrategies without parameters.
+var (
+	// HashSHA256 replaces a value with the hex encoding of its SHA-256
+	// hash, so that equal values can still be matched. Values that are
+	// easy to guess, such as phone numbers, can be recovered from it.
+	HashSHA256 AnonymizeStrategy = AnonymizeFunc(func(value string) string {
+		sum := sha256.Sum256([]byte(value))
+		return hex.EncodeToString(sum[:])
+	})
+
+	// Mask replaces each character of a value with '*'.
+	Mask AnonymizeStrategy = AnonymizeFunc(func(value string) string {
+		return strings.Repeat("*", utf8.RuneCountInString(value))
+	})
+
+	// Redact replaces a value with the empty string.
+	Redact AnonymizeStrategy = Constant("")
+)
+
+// Truncate returns an AnonymizeStrategy keeping the first n characters of
+// a value.
+func Truncate(n int) AnonymizeStrategy {
+	return AnonymizeFunc(func(value string) string {
+		k := 0
+		for i := range value {
+			if k == n {
+				return value[:i]
+			}
+			k++
+		}
+		return value
+	})
+}
+
+// Constant returns an AnonymizeStrategy replacing every value with s.
+func Constant(s string) AnonymizeStrategy {
+	return AnonymizeFunc(func(string) string { return s })
+}
+
+// Anonymize returns a copy of c with its value replaced as determined by s.
+// Quoted is kept, while OriginalBytes, which holds the value as it was
+// read, is cleared. NULL columns are returned unchanged.
+func (c Column) Anonymize(s AnonymizeStrategy) Column {
+	if c.IsNull {
+		return c
+	}
+	c.Value = s.AnonymizeValue(c.Value)
+	c.OriginalBytes = ""
+	return c
+}
+
+// AddAnonymizer registers s to anonymize the column at columnIndex of
+// every record read by r, or every column if columnIndex is -1, using
+// Column.Anonymize. It is a transform added like with AddTransformer.
+func (r *Reader) AddAnonymizer(columnIndex int, s AnonymizeStrategy) {
+	r.AddTransformer(columnIndex, func(c Column) Column { return c.Anonymize(s) })
+}
@@ -0,0 +1,72 @@
+package csv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnAnonymize(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Strategy AnonymizeStrategy
+		Value    string
+		Want     string
+	}{
+		{Name: "HashSHA256", Strategy: HashSHA256, Value: "abc", Want: "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad"},
+		{Name: "Mask", Strategy: Mask, Value: "Jürgen", Want: "******"},
+		{Name: "MaskEmpty", Strategy: Mask, Value: "", Want: ""},
+		{Name: "Truncate", Strategy: Truncate(3), Value: "Jürgen", Want: "Jür"},
+		{Name: "TruncateShort", Strategy: Truncate(10), Value: "Jürgen", Want: "Jürgen"},
+		{Name: "TruncateZero", Strategy: Truncate(0), Value: "Jürgen", Want: ""},
+		{Name: "Constant", Strategy: Constant("REDACTED"), Value: "078-05-1120", Want: "REDACTED"},
+		{Name: "Redact", Strategy: Redact, Value: "078-05-1120", Want: ""},
+		{Name: "Func", Strategy: AnonymizeFunc(strings.ToUpper), Value: "ann", Want: "ANN"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			col := Column{Value: tt.Value, Quoted: true, OriginalBytes: `"` + tt.Value + `"`}
+			got := col.Anonymize(tt.Strategy)
+			want := Column{Value: tt.Want, Quoted: true}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Anonymize() = %+v, want %+v", got, want)
+			}
+			// Strategies can be reused.
+			if got := col.Anonymize(tt.Strategy); got.Value != tt.Want {
+				t.Errorf("second Anonymize() = %q, want %q", got.Value, tt.Want)
+			}
+		})
+	}
+
+	null := Column{IsNull: true}
+	if got := null.Anonymize(Constant("x")); !reflect.DeepEqual(got, null) {
+		t.Errorf("Anonymize() of NULL = %+v, want %+v", got, null)
+	}
+}
+
+func TestReaderAddAnonymizer(t *testing.T) {
+	r := NewReader(strings.NewReader("ann,ann@example.com,\"4111111111111111\"\nbob,bob@example.com,5500000000000004\n"))
+	r.CaptureRaw = true
+	r.AddAnonymizer(1, HashSHA256)
+	r.AddAnonymizer(2, Truncate(4))
+	records, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	for i, want := range [][]string{
+		{"ann", HashSHA256.AnonymizeValue("ann@example.com"), "4111"},
+		{"bob", HashSHA256.AnonymizeValue("bob@example.com"), "5500"},
+	} {
+		if got := columnValues(records[i]); !reflect.DeepEqual(got, want) {
+			t.Errorf("record %d = %q, want %q", i, got, want)
+		}
+		for _, col := range records[i][1:] {
+			if col.OriginalBytes != "" {
+				t.Errorf("record %d: anonymized column kept OriginalBytes %q", i, col.OriginalBytes)
+			}
+		}
+	}
+	if !records[0][2].Quoted {
+		t.Errorf("anonymized column lost Quoted")
+	}
+}
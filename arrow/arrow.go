@@ -0,0 +1,134 @@
+// Package arrow converts csv.Tables to and from Apache Arrow records.
+//
+// It lives in its own module so that the csv package itself does not
+// depend on Arrow. Its name is the same as that of Arrow's own package,
+// so one of them has to be imported under another name alongside the
+// other.
+package arrow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+
+	csv "github.com/terorie/go-quotecsv"
+)
+
+// ErrUnsupportedType is returned by TableFromArrow for columns of a type
+// other than those created by ToArrow.
+var ErrUnsupportedType = errors.New("csv/arrow: unsupported column type")
+
+// ToArrow returns an Arrow record holding the rows of t, allocated with
+// memory.DefaultAllocator. The caller must call Release on it when done.
+//
+// Each column is converted to the type found by Table.InferTypes: int64,
+// float64 or boolean if all its values convert to it, or utf8 otherwise.
+// All fields are nullable. NULL columns, missing fields and, in columns
+// other than utf8 ones, blank values are null.
+func ToArrow(t *csv.Table) (arrow.Record, error) {
+	types := t.InferTypes()
+	fields := make([]arrow.Field, len(t.Headers))
+	for i, name := range t.Headers {
+		fields[i] = arrow.Field{Name: name, Type: arrowType(types[i]), Nullable: true}
+	}
+	b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema(fields, nil))
+	defer b.Release()
+
+	for i, typ := range types {
+		fb := b.Field(i)
+		fb.Reserve(len(t.Rows))
+		for r, record := range t.Rows {
+			var col csv.Column
+			if i < len(record) {
+				col = record[i]
+			} else {
+				col.IsNull = true
+			}
+			if col.IsNull || typ != csv.TypeString && col.IsMissing() {
+				fb.AppendNull()
+				continue
+			}
+			var err error
+			switch fb := fb.(type) {
+			case *array.Int64Builder:
+				var v int64
+				v, err = col.AsInt64()
+				fb.Append(v)
+			case *array.Float64Builder:
+				var v float64
+				v, err = col.AsFloat64()
+				fb.Append(v)
+			case *array.BooleanBuilder:
+				var v bool
+				v, err = col.AsBool()
+				fb.Append(v)
+			case *array.StringBuilder:
+				fb.Append(col.Value)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("csv/arrow: row %d, column %q: %w", r, t.Headers[i], err)
+			}
+		}
+	}
+	return b.NewRecord(), nil
+}
+
+// arrowType returns the Arrow type of a column of type typ.
+func arrowType(typ csv.ColumnType) arrow.DataType {
+	switch typ {
+	case csv.TypeInt:
+		return arrow.PrimitiveTypes.Int64
+	case csv.TypeFloat:
+		return arrow.PrimitiveTypes.Float64
+	case csv.TypeBool:
+		return arrow.FixedWidthTypes.Boolean
+	}
+	return arrow.BinaryTypes.String
+}
+
+// TableFromArrow returns a Table holding the rows of rec, with one column
+// per field of rec. It supports the column types created by ToArrow.
+// Null values become NULL columns. Numbers are formatted as by
+// strconv.FormatInt and strconv.FormatFloat with format 'g', and booleans
+// as "true" or "false". For other column types, TableFromArrow returns an
+// error wrapping ErrUnsupportedType.
+func TableFromArrow(rec arrow.Record) (csv.Table, error) {
+	ncols, nrows := int(rec.NumCols()), int(rec.NumRows())
+	t := csv.Table{Headers: make([]string, ncols)}
+	if nrows > 0 {
+		t.Rows = make([][]csv.Column, nrows)
+		for r := range t.Rows {
+			t.Rows[r] = make([]csv.Column, ncols)
+		}
+	}
+	for i := 0; i < ncols; i++ {
+		field := rec.Schema().Field(i)
+		t.Headers[i] = field.Name
+		var value func(r int) string
+		switch arr := rec.Column(i).(type) {
+		case *array.Int64:
+			value = func(r int) string { return strconv.FormatInt(arr.Value(r), 10) }
+		case *array.Float64:
+			value = func(r int) string { return strconv.FormatFloat(arr.Value(r), 'g', -1, 64) }
+		case *array.Boolean:
+			value = func(r int) string { return strconv.FormatBool(arr.Value(r)) }
+		case *array.String:
+			value = arr.Value
+		default:
+			return csv.Table{}, fmt.Errorf("%w %s of column %q", ErrUnsupportedType, field.Type, field.Name)
+		}
+		arr := rec.Column(i)
+		for r := 0; r < nrows; r++ {
+			if arr.IsNull(r) {
+				t.Rows[r][i] = csv.Column{IsNull: true}
+			} else {
+				t.Rows[r][i] = csv.Column{Value: value(r)}
+			}
+		}
+	}
+	return t, nil
+}
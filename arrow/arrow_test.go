@@ -0,0 +1,106 @@
+package arrow
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/apache/arrow/go/v17/arrow"
+	"github.com/apache/arrow/go/v17/arrow/array"
+	"github.com/apache/arrow/go/v17/arrow/memory"
+
+	csv "github.com/terorie/go-quotecsv"
+)
+
+func c(s string) csv.Column { return csv.Column{Value: s} }
+
+func TestToArrow(t *testing.T) {
+	tbl := csv.NewTable([]string{"id", "score", "active", "name"}, [][]csv.Column{
+		{c("1"), c("9.5"), c("true"), c("Ann")},
+		{c("2"), {IsNull: true}, c("false"), {Value: "", Quoted: true}},
+		{c("3"), c("10"), c("")},
+	})
+	rec, err := ToArrow(tbl)
+	if err != nil {
+		t.Fatalf("ToArrow() error: %v", err)
+	}
+	defer rec.Release()
+
+	wantTypes := []arrow.DataType{
+		arrow.PrimitiveTypes.Int64,
+		arrow.PrimitiveTypes.Float64,
+		arrow.FixedWidthTypes.Boolean,
+		arrow.BinaryTypes.String,
+	}
+	for i, want := range wantTypes {
+		f := rec.Schema().Field(i)
+		if f.Name != tbl.Headers[i] || !arrow.TypeEqual(f.Type, want) || !f.Nullable {
+			t.Errorf("field %d = %v, want nullable %s of type %s", i, f, tbl.Headers[i], want)
+		}
+	}
+	if rec.NumRows() != 3 {
+		t.Fatalf("NumRows() = %d, want 3", rec.NumRows())
+	}
+
+	ids := rec.Column(0).(*array.Int64)
+	if got := ids.Int64Values(); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Errorf("id = %v, want [1 2 3]", got)
+	}
+	scores := rec.Column(1).(*array.Float64)
+	if scores.Value(0) != 9.5 || !scores.IsNull(1) || scores.Value(2) != 10 {
+		t.Errorf("score = %v, want [9.5 (null) 10]", scores)
+	}
+	active := rec.Column(2).(*array.Boolean)
+	if !active.Value(0) || active.Value(1) || !active.IsNull(2) {
+		t.Errorf("active = %v, want [true false (null)]", active)
+	}
+	names := rec.Column(3).(*array.String)
+	if names.Value(0) != "Ann" || names.IsNull(1) || names.Value(1) != "" || !names.IsNull(2) {
+		t.Errorf("name = %v, want [Ann \"\" (null)]", names)
+	}
+}
+
+func TestTableFromArrow(t *testing.T) {
+	tbl := csv.NewTable([]string{"id", "score", "active", "name"}, [][]csv.Column{
+		{c("1"), c("9.5"), c("true"), c("Ann")},
+		{c("-2"), {IsNull: true}, c("false"), c("")},
+		{c("3"), c("1e+21"), {IsNull: true}, {IsNull: true}},
+	})
+	rec, err := ToArrow(tbl)
+	if err != nil {
+		t.Fatalf("ToArrow() error: %v", err)
+	}
+	defer rec.Release()
+	out, err := TableFromArrow(rec)
+	if err != nil {
+		t.Fatalf("TableFromArrow() error: %v", err)
+	}
+	if !reflect.DeepEqual(out, *tbl) {
+		t.Errorf("TableFromArrow(ToArrow()) =\n%v\nwant\n%v", out, *tbl)
+	}
+
+	empty, err := ToArrow(csv.NewTable([]string{"a"}, nil))
+	if err != nil {
+		t.Fatalf("ToArrow() of empty table error: %v", err)
+	}
+	defer empty.Release()
+	out, err = TableFromArrow(empty)
+	if err != nil {
+		t.Fatalf("TableFromArrow() of empty record error: %v", err)
+	}
+	if want := (csv.Table{Headers: []string{"a"}}); !reflect.DeepEqual(out, want) {
+		t.Errorf("TableFromArrow() of empty record = %v, want %v", out, want)
+	}
+}
+
+func TestTableFromArrowUnsupported(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "n", Type: arrow.PrimitiveTypes.Int32}}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int32Builder).Append(1)
+	rec := b.NewRecord()
+	defer rec.Release()
+	if _, err := TableFromArrow(rec); !errors.Is(err, ErrUnsupportedType) {
+		t.Errorf("TableFromArrow() of int32 column error = %v, want %v", err, ErrUnsupportedType)
+	}
+}
@@ -0,0 +1,61 @@
+package csv
+
+// A BatchWriter collects records in memory and writes them to a Writer
+// in batches, so that a destination with a high cost per write, such as
+// a network connection, receives few large writes.
+type BatchWriter struct {
+	// BatchSize is the number of records collected before they are
+	// written. If BatchSize is not positive, records are only written
+	// by Flush and Close.
+	BatchSize int
+
+	w       *Writer
+	pending [][]Column
+}
+
+// NewBatchWriter returns a BatchWriter writing batches of batchSize
+// records to w.
+func NewBatchWriter(w *Writer, batchSize int) *BatchWriter {
+	return &BatchWriter{BatchSize: batchSize, w: w}
+}
+
+// Write adds a copy of record to the current batch, and writes the batch
+// if it holds BatchSize records.
+func (b *BatchWriter) Write(record []Column) error {
+	b.pending = append(b.pending, append([]Column(nil), record...))
+	if b.BatchSize > 0 && len(b.pending) >= b.BatchSize {
+		return b.Flush()
+	}
+	return nil
+}
+
+// Flush writes the records of the current batch to the underlying Writer
+// and flushes it. The Writer's AutoFlush setting is ignored while writing
+// the batch. The batch is emptied even if an error occurs; the records
+// following the one that failed are not written.
+func (b *BatchWriter) Flush() error {
+	autoFlush := b.w.AutoFlush
+	b.w.AutoFlush = false
+	var err error
+	for _, record := range b.pending {
+		if err = b.w.Write(record); err != nil {
+			break
+		}
+	}
+	b.w.AutoFlush = autoFlush
+	for i := range b.pending {
+		b.pending[i] = nil
+	}
+	b.pending = b.pending[:0]
+	if errFlush := b.w.flush(); err == nil {
+		err = errFlush
+	}
+	return err
+}
+
+// Close writes any pending records like Flush. It does not close the
+// io.Writer underlying the Writer.
+// It implements io.Closer.
+func (b *BatchWriter) Close() error {
+	return b.Flush()
+}
@@ -0,0 +1,61 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+func TestBatchWriter(t *testing.T) {
+	cw := &chunkWriter{}
+	w := NewWriter(cw)
+	w.AutoFlush = true
+	var bw io.Closer = NewBatchWriter(w, 2)
+	b := bw.(*BatchWriter)
+
+	record := []Column{c("a"), c("1")}
+	if err := b.Write(record); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	record[1] = c("2") // The batch holds a copy.
+	if len(cw.chunks) != 0 {
+		t.Errorf("Write() reached the writer before the batch was full: %q", cw.chunks)
+	}
+	if err := b.Write(record); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if want := []string{"a,1\na,2\n"}; !reflect.DeepEqual(cw.chunks, want) {
+		t.Errorf("full batch written as %q, want %q", cw.chunks, want)
+	}
+
+	b.Write([]Column{q("b,c")})
+	if err := bw.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if want := []string{"a,1\na,2\n", "\"b,c\"\n"}; !reflect.DeepEqual(cw.chunks, want) {
+		t.Errorf("Close() wrote %q, want %q", cw.chunks, want)
+	}
+	if !w.AutoFlush {
+		t.Errorf("Flush() did not restore AutoFlush")
+	}
+	if n := w.Metrics().RecordsWritten; n != 3 {
+		t.Errorf("RecordsWritten = %d, want 3", n)
+	}
+}
+
+func TestBatchWriterError(t *testing.T) {
+	errBoom := errors.New("boom")
+	b := NewBatchWriter(NewWriter(&chunkWriter{err: errBoom}), 0)
+	for i := 0; i < 10; i++ {
+		if err := b.Write([]Column{c("x")}); err != nil {
+			t.Fatalf("Write() error with BatchSize 0: %v", err)
+		}
+	}
+	if err := b.Close(); err != errBoom {
+		t.Errorf("Close() error = %v, want %v", err, errBoom)
+	}
+	if len(b.pending) != 0 {
+		t.Errorf("Close() kept %d pending records after error", len(b.pending))
+	}
+}
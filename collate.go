@@ -0,0 +1,115 @@
+package csv
+
+import (
+	"sort"
+	"strings"
+)
+
+// A Collator computes sort keys for locale-aware ordering.
+// Keys returned by Key are compared byte-wise.
+//
+// A *collate.Collator from golang.org/x/text/collate can be wrapped with
+// CollatorFunc:
+//
+//	c := collate.New(language.German)
+//	var buf collate.Buffer
+//	collator := csv.CollatorFunc(func(s string) []byte {
+//		buf.Reset()
+//		return c.KeyFromString(&buf, s)
+//	})
+type Collator interface {
+	Key(s string) []byte
+}
+
+// The CollatorFunc type is an adapter to allow the use of ordinary
+// functions as Collators.
+type CollatorFunc func(s string) []byte
+
+// Key calls f(s).
+func (f CollatorFunc) Key(s string) []byte {
+	return f(s)
+}
+
+// SortKey returns the sort key of the column's value under collator.
+// Sort keys of different columns can be compared with the usual
+// string comparison operators.
+func (c Column) SortKey(collator Collator) string {
+	return string(collator.Key(c.Value))
+}
+
+// ByColumnCollated returns a less function comparing records by the sort key
+// of their idx'th column. Records that do not have an idx'th column sort first.
+// It is meant to be used with sort.Slice:
+//
+//	less := csv.ByColumnCollated(0, collator)
+//	sort.Slice(records, func(i, j int) bool { return less(records[i], records[j]) })
+func ByColumnCollated(idx int, c Collator) func(a, b []Column) bool {
+	return func(a, b []Column) bool {
+		if idx >= len(b) {
+			return false
+		}
+		if idx >= len(a) {
+			return true
+		}
+		return a[idx].SortKey(c) < b[idx].SortKey(c)
+	}
+}
+
+// Compare returns an integer comparing c to other. Values are compared
+// like strings.Compare; if they are equal, an unquoted column sorts before
+// a quoted one. The result is 0 if c == other, -1 if c < other,
+// and +1 if c > other.
+func (c Column) Compare(other Column) int {
+	if n := strings.Compare(c.Value, other.Value); n != 0 {
+		return n
+	}
+	switch {
+	case c.Quoted == other.Quoted:
+		return 0
+	case other.Quoted:
+		return -1
+	}
+	return +1
+}
+
+// ColumnLess reports whether a sorts before b according to Column.Compare.
+func ColumnLess(a, b Column) bool {
+	return a.Compare(b) < 0
+}
+
+// compareRecords compares a and b column by column with Column.Compare.
+// If one is a prefix of the other, the shorter one sorts first.
+func compareRecords(a, b []Column) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if n := a[i].Compare(b[i]); n != 0 {
+			return n
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return +1
+	}
+	return 0
+}
+
+// SortRows sorts rows in place by their keyCol'th column using
+// Column.Compare. Rows that do not have a keyCol'th column sort first.
+// If keyCol is negative, rows are compared column by column.
+// The sort is stable.
+func SortRows(rows [][]Column, keyCol int) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i], rows[j]
+		if keyCol < 0 {
+			return compareRecords(a, b) < 0
+		}
+		if keyCol >= len(b) {
+			return false
+		}
+		if keyCol >= len(a) {
+			return true
+		}
+		return a[keyCol].Compare(b[keyCol]) < 0
+	})
+}
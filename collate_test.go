@@ -0,0 +1,129 @@
+package csv
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// germanCollator approximates DIN 5007-1 ordering: umlauts sort with
+// their base letter, and the original value breaks ties.
+var germanCollator = CollatorFunc(func(s string) []byte {
+	primary := strings.NewReplacer(
+		"ä", "a", "ö", "o", "ü", "u",
+		"Ä", "a", "Ö", "o", "Ü", "u", "ß", "ss",
+	).Replace(strings.ToLower(s))
+	return []byte(primary + "\x00" + s)
+})
+
+func TestByColumnCollated(t *testing.T) {
+	records := [][]Column{
+		{c("zebra"), c("1")},
+		{c("äpfel"), c("2")},
+		{c("apfel"), c("3")},
+		{c("über"), c("4")},
+		{c("birne"), c("5")},
+		{},
+	}
+	less := ByColumnCollated(0, germanCollator)
+	sort.Slice(records, func(i, j int) bool { return less(records[i], records[j]) })
+
+	var got []string
+	for _, record := range records {
+		if len(record) == 0 {
+			got = append(got, "")
+			continue
+		}
+		got = append(got, record[0].Value)
+	}
+	want := []string{"", "apfel", "äpfel", "birne", "über", "zebra"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sorted:\ngot  %q\nwant %q", got, want)
+	}
+}
+
+func TestSortKey(t *testing.T) {
+	a, ae, z := c("a"), c("ä"), c("z")
+	if !(a.SortKey(germanCollator) < ae.SortKey(germanCollator)) {
+		t.Error("a should sort before ä")
+	}
+	if !(ae.SortKey(germanCollator) < z.SortKey(germanCollator)) {
+		t.Error("ä should sort before z")
+	}
+}
+
+func TestColumnCompare(t *testing.T) {
+	tests := []struct {
+		A, B Column
+		Want int
+	}{
+		{c("a"), c("b"), -1},
+		{c("b"), c("a"), +1},
+		{c(""), c("a"), -1},
+		{c(""), c(""), 0},
+		{c("x"), q("x"), -1},
+		{q("x"), c("x"), +1},
+		{q("x"), q("x"), 0},
+		{q(""), c(""), +1},
+		{c("z"), c("ä"), -1}, // Byte order, not collation
+		{c("日本"), c("日本語"), -1},
+		{c("Z"), c("a"), -1},
+	}
+	for _, tt := range tests {
+		if got := tt.A.Compare(tt.B); got != tt.Want {
+			t.Errorf("%v.Compare(%v) = %d, want %d", tt.A, tt.B, got, tt.Want)
+		}
+		if got := ColumnLess(tt.A, tt.B); got != (tt.Want < 0) {
+			t.Errorf("ColumnLess(%v, %v) = %v, want %v", tt.A, tt.B, got, tt.Want < 0)
+		}
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	rows := [][]Column{
+		{c("3"), q("b")},
+		{c("1"), c("b")},
+		{c("2")},
+		{c("1"), q("b")},
+		{c("0"), c("日本")},
+		{c("4"), c("")},
+	}
+	sorted := func(keyCol int) [][]Column {
+		out := append([][]Column(nil), rows...)
+		SortRows(out, keyCol)
+		return out
+	}
+
+	want := [][]Column{
+		{c("2")},
+		{c("4"), c("")},
+		{c("1"), c("b")},
+		{c("3"), q("b")},
+		{c("1"), q("b")},
+		{c("0"), c("日本")},
+	}
+	if got := sorted(1); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortRows(rows, 1) =\n%v, want\n%v", got, want)
+	}
+
+	want = [][]Column{
+		{c("0"), c("日本")},
+		{c("1"), c("b")},
+		{c("1"), q("b")},
+		{c("2")},
+		{c("3"), q("b")},
+		{c("4"), c("")},
+	}
+	if got := sorted(-1); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortRows(rows, -1) =\n%v, want\n%v", got, want)
+	}
+
+	// Equal rows keep their order.
+	same := [][]Column{{q("x"), c("1")}, {c("x"), c("2")}, {q("x"), c("3")}, {c("x"), c("4")}}
+	SortRows(same, 0)
+	want = [][]Column{{c("x"), c("2")}, {c("x"), c("4")}, {q("x"), c("1")}, {q("x"), c("3")}}
+	if !reflect.DeepEqual(same, want) {
+		t.Errorf("SortRows() of equal values = %v, want %v", same, want)
+	}
+}
@@ -1,5 +1,10 @@
 package csv
 
+import (
+	"strings"
+	"unicode"
+)
+
 // A Column of a CSV row.
 type Column struct {
 	// When used with a Reader, signals whether the field was quoted.
@@ -7,8 +12,83 @@ type Column struct {
 	Quoted bool
 	// Value of the column
 	Value string
+	// IsNull marks a SQL NULL, as read or written using NullValue.
+	// The Value of a NULL column is ignored by a Writer.
+	IsNull bool
+	// OriginalBytes holds the field as it appeared in the input, including
+	// any quotes and escapes, if it was read by a Reader with CaptureRaw
+	// set. Line breaks in it are normalized to \n like in Value.
+	// It is empty otherwise and is ignored by a Writer. It is a string
+	// rather than a []byte so that Columns remain comparable with == and
+	// usable as map keys; use []byte(c.OriginalBytes) where bytes are
+	// needed.
+	OriginalBytes string
 }
 
 func (c *Column) String() string {
 	return c.Value
 }
+
+// IsEmpty reports whether the column's value is the empty string.
+// A quoted empty field ("") is empty too.
+func (c Column) IsEmpty() bool {
+	return len(c.Value) == 0
+}
+
+// IsBlank reports whether the column's value is empty or consists only
+// of white space, as defined by Unicode. Every empty column is blank,
+// but "  " is blank without being empty.
+func (c Column) IsBlank() bool {
+	return strings.TrimSpace(c.Value) == ""
+}
+
+// IsMissing reports whether the column holds no data: it is either
+// NULL or blank. Unlike IsEmpty and IsBlank, IsMissing is true for a
+// NULL column regardless of its value.
+func (c Column) IsMissing() bool {
+	return c.IsNull || c.IsBlank()
+}
+
+// Sanitize returns a copy of the column with control characters removed
+// from its value, including line breaks such as \n, \r and U+0085.
+// Tabs are kept. Every run of other white space, such as spaces,
+// no-break spaces and the zero-width space U+200B, is replaced by a
+// single ASCII space. The bidirectional formatting
+// characters U+202A to U+202E and U+2066 to U+2069, which can be used to
+// disguise text, are removed as well. Other format characters, such as
+// the zero-width joiner used in emoji sequences, are kept.
+func (c Column) Sanitize() Column {
+	var b strings.Builder
+	b.Grow(len(c.Value))
+	space := false
+	for _, r := range c.Value {
+		switch {
+		case r == '\t':
+		case unicode.IsControl(r),
+			'\u202a' <= r && r <= '\u202e',
+			'\u2066' <= r && r <= '\u2069':
+			continue
+		case unicode.IsSpace(r) || r == '\u200b':
+			if !space {
+				b.WriteByte(' ')
+				space = true
+			}
+			continue
+		}
+		b.WriteRune(r)
+		space = false
+	}
+	c.Value = b.String()
+	return c
+}
+
+// AsCSV returns the column as a Writer with the given Comma would write it,
+// including any quotes, but without the delimiter or line terminator.
+func (c Column) AsCSV(comma rune) string {
+	var b strings.Builder
+	w := NewWriter(&b)
+	w.Comma = comma
+	w.writeField(c, &WriteMetrics{})
+	w.w.Flush()
+	return b.String()
+}
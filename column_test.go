@@ -0,0 +1,107 @@
+package csv
+
+import "testing"
+
+func TestColumnBlank(t *testing.T) {
+	tests := []struct {
+		value        string
+		empty, blank bool
+	}{
+		{"", true, true},
+		{"   ", false, true},
+		{"\t", false, true},
+		{"\n", false, true},
+		{" ", false, true},
+		{" a ", false, false},
+		{"0", false, false},
+	}
+	for _, tt := range tests {
+		for _, col := range []Column{c(tt.value), q(tt.value)} {
+			if got := col.IsEmpty(); got != tt.empty {
+				t.Errorf("%+v.IsEmpty() = %v, want %v", col, got, tt.empty)
+			}
+			if got := col.IsBlank(); got != tt.blank {
+				t.Errorf("%+v.IsBlank() = %v, want %v", col, got, tt.blank)
+			}
+		}
+	}
+}
+
+func TestColumnMissing(t *testing.T) {
+	tests := []struct {
+		col  Column
+		want bool
+	}{
+		{c(""), true},
+		{q(" \t"), true},
+		{Column{IsNull: true}, true},
+		{Column{IsNull: true, Value: "x"}, true},
+		{c("x"), false},
+		{q("0"), false},
+	}
+	for _, tt := range tests {
+		if got := tt.col.IsMissing(); got != tt.want {
+			t.Errorf("%+v.IsMissing() = %v, want %v", tt.col, got, tt.want)
+		}
+	}
+}
+
+func TestColumnAsCSV(t *testing.T) {
+	tests := []struct {
+		col   Column
+		comma rune
+		want  string
+	}{
+		{c("hello"), ',', "hello"},
+		{c("hello,world"), ',', `"hello,world"`},
+		{c("hello,world"), ';', "hello,world"},
+		{c("hello;world"), ';', `"hello;world"`},
+		{c("tab\there"), '\t', "\"tab\there\""},
+		{c(`say "hi"`), ',', `"say ""hi"""`},
+		{c("two\nlines"), ',', "\"two\nlines\""},
+		{c("carriage\rreturn"), ',', "\"carriage\rreturn\""},
+		{c(" leading space"), ',', " leading space"},
+		{q(" leading space"), ',', `" leading space"`},
+		{c(""), ',', ""},
+		{q(""), ',', `""`},
+		{q("forced"), ',', `"forced"`},
+	}
+	for _, tt := range tests {
+		if got := tt.col.AsCSV(tt.comma); got != tt.want {
+			t.Errorf("%+v.AsCSV(%q) = %q, want %q", tt.col, tt.comma, got, tt.want)
+		}
+	}
+}
+
+func TestColumnSanitize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"plain", "plain"},
+		{"nul\x00byte", "nulbyte"},
+		{"\x00\x00", ""},
+		{"bell\a and\x1b[31m escape\x7f", "bell and[31m escape"},
+		{"c1\u0085\u0090control", "c1control"},
+		{"tab\tkept", "tab\tkept"},
+		{"two\r\nlines", "twolines"},
+		{"a\nb", "ab"},
+		{"a\rb", "ab"},
+		{"a \n b\v\fc", "a bc"},
+		{"no\u00a0break \u3000spaces", "no break spaces"},
+		{"zero\u200bwidth", "zero width"},
+		{"a \u200b b", "a b"},
+		{"user\u202egnp.exe", "usergnp.exe"},
+		{"\u2067isolate\u2069", "isolate"},
+		{"family \U0001F468\u200d\U0001F469\u200d\U0001F467", "family \U0001F468\u200d\U0001F469\u200d\U0001F467"},
+		{"  padded  ", " padded "},
+		{"ünïcödé", "ünïcödé"},
+	}
+	for _, tt := range tests {
+		for _, col := range []Column{c(tt.in), q(tt.in)} {
+			got := col.Sanitize()
+			if got.Value != tt.want || got.Quoted != col.Quoted {
+				t.Errorf("%+q.Sanitize() = %+q (quoted %v), want %+q", col.Value, got.Value, got.Quoted, tt.want)
+			}
+		}
+	}
+}
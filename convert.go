@@ -0,0 +1,59 @@
+package csv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// conversionError describes a failure to convert value to the Go type typ.
+// If err is a *strconv.NumError, only its cause is kept, as the message
+// already names the value.
+func conversionError(value, typ string, err error) error {
+	if ne, ok := err.(*strconv.NumError); ok {
+		err = ne.Err
+	}
+	return fmt.Errorf("csv: cannot convert %q to %s: %w", value, typ, err)
+}
+
+// AsInt64 interprets the column's value as a base 10 integer.
+func (c Column) AsInt64() (int64, error) {
+	i, err := strconv.ParseInt(c.Value, 10, 64)
+	if err != nil {
+		return 0, conversionError(c.Value, "int64", err)
+	}
+	return i, nil
+}
+
+// AsFloat64 interprets the column's value as a floating-point number,
+// as accepted by strconv.ParseFloat.
+func (c Column) AsFloat64() (float64, error) {
+	f, err := strconv.ParseFloat(c.Value, 64)
+	if err != nil {
+		return 0, conversionError(c.Value, "float64", err)
+	}
+	return f, nil
+}
+
+// AsBool interprets the column's value as a boolean. It accepts "true",
+// "false", "1", "0", "yes" and "no", ignoring case.
+func (c Column) AsBool() (bool, error) {
+	switch strings.ToLower(c.Value) {
+	case "true", "1", "yes":
+		return true, nil
+	case "false", "0", "no":
+		return false, nil
+	}
+	return false, conversionError(c.Value, "bool", strconv.ErrSyntax)
+}
+
+// AsTime interprets the column's value as a time formatted according to
+// layout, as accepted by time.Parse.
+func (c Column) AsTime(layout string) (time.Time, error) {
+	t, err := time.Parse(layout, c.Value)
+	if err != nil {
+		return time.Time{}, conversionError(c.Value, "time.Time", err)
+	}
+	return t, nil
+}
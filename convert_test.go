@@ -0,0 +1,73 @@
+package csv
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestColumnConversions(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Value string
+		Conv  func(Column) (interface{}, error)
+		Want  interface{}
+		Error error // Wrapped error, or nil
+	}{
+		{Name: "Int", Value: "42", Conv: asInt64, Want: int64(42)},
+		{Name: "IntZero", Value: "0", Conv: asInt64, Want: int64(0)},
+		{Name: "IntNegative", Value: "-7", Conv: asInt64, Want: int64(-7)},
+		{Name: "IntMax", Value: "9223372036854775807", Conv: asInt64, Want: int64(math.MaxInt64)},
+		{Name: "IntOverflow", Value: "9223372036854775808", Conv: asInt64, Error: strconv.ErrRange},
+		{Name: "IntEmpty", Value: "", Conv: asInt64, Error: strconv.ErrSyntax},
+		{Name: "IntFloat", Value: "1.5", Conv: asInt64, Error: strconv.ErrSyntax},
+		{Name: "Float", Value: "1.5", Conv: asFloat64, Want: 1.5},
+		{Name: "FloatZero", Value: "0", Conv: asFloat64, Want: float64(0)},
+		{Name: "FloatExp", Value: "-2e3", Conv: asFloat64, Want: float64(-2000)},
+		{Name: "FloatOverflow", Value: "1e400", Conv: asFloat64, Error: strconv.ErrRange},
+		{Name: "FloatEmpty", Value: "", Conv: asFloat64, Error: strconv.ErrSyntax},
+		{Name: "BoolTrue", Value: "true", Conv: asBool, Want: true},
+		{Name: "BoolTRUE", Value: "TRUE", Conv: asBool, Want: true},
+		{Name: "BoolFalse", Value: "False", Conv: asBool, Want: false},
+		{Name: "BoolOne", Value: "1", Conv: asBool, Want: true},
+		{Name: "BoolZero", Value: "0", Conv: asBool, Want: false},
+		{Name: "BoolYes", Value: "Yes", Conv: asBool, Want: true},
+		{Name: "BoolNo", Value: "NO", Conv: asBool, Want: false},
+		{Name: "BoolEmpty", Value: "", Conv: asBool, Error: strconv.ErrSyntax},
+		{Name: "BoolOther", Value: "y", Conv: asBool, Error: strconv.ErrSyntax},
+		{Name: "Time", Value: "2021-05-01", Conv: asDate, Want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{Name: "TimeEmpty", Value: "", Conv: asDate},
+		{Name: "TimeBad", Value: "2021-13-01", Conv: asDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got, err := tt.Conv(q(tt.Value))
+			if tt.Want != nil {
+				if err != nil {
+					t.Fatalf("error: %v", err)
+				}
+				if got != tt.Want {
+					t.Errorf("got %v (%T), want %v (%T)", got, got, tt.Want, tt.Want)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("got %v, want error", got)
+			}
+			if tt.Error != nil && !errors.Is(err, tt.Error) {
+				t.Errorf("error %v does not wrap %v", err, tt.Error)
+			}
+			if !strings.Contains(err.Error(), strconv.Quote(tt.Value)) {
+				t.Errorf("error %q does not name the value %q", err, tt.Value)
+			}
+		})
+	}
+}
+
+func asInt64(c Column) (interface{}, error)   { return c.AsInt64() }
+func asFloat64(c Column) (interface{}, error) { return c.AsFloat64() }
+func asBool(c Column) (interface{}, error)    { return c.AsBool() }
+func asDate(c Column) (interface{}, error)    { return c.AsTime("2006-01-02") }
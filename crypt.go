@@ -0,0 +1,61 @@
+package csv
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+)
+
+// ErrInvalidCiphertext is returned by Column.Decrypt for values that
+// were not produced by Column.Encrypt.
+var ErrInvalidCiphertext = errors.New("csv: invalid ciphertext")
+
+// Encrypt returns a copy of c with its value encrypted using AES-GCM under
+// key, which must be 16, 24 or 32 bytes long. The new value is the base64
+// encoding of a random nonce followed by the ciphertext. OriginalBytes,
+// which would hold the plaintext as it was read, is cleared.
+func (c Column) Encrypt(key []byte) (Column, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Column{}, err
+	}
+	nonce := make([]byte, gcm.NonceSize(), gcm.NonceSize()+len(c.Value)+gcm.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return Column{}, err
+	}
+	sealed := gcm.Seal(nonce, nonce, []byte(c.Value), nil)
+	c.Value = base64.StdEncoding.EncodeToString(sealed)
+	c.OriginalBytes = ""
+	return c, nil
+}
+
+// Decrypt reverses Encrypt, returning a copy of c with its value decrypted
+// using key. OriginalBytes is cleared, as it no longer matches the value.
+func (c Column) Decrypt(key []byte) (Column, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Column{}, err
+	}
+	sealed, err := base64.StdEncoding.DecodeString(c.Value)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return Column{}, ErrInvalidCiphertext
+	}
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return Column{}, ErrInvalidCiphertext
+	}
+	c.Value = string(plaintext)
+	c.OriginalBytes = ""
+	return c, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
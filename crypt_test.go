@@ -0,0 +1,81 @@
+package csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	key := bytes.Repeat([]byte{0x42}, 32)
+	for _, value := range []string{"", "4111111111111111", "078-05-1120", strings.Repeat("x", 1000), "ünïcödé,\"quoted\"\n"} {
+		col := Column{Value: value, Quoted: true}
+		enc, err := col.Encrypt(key)
+		if err != nil {
+			t.Fatalf("Encrypt(%q) error: %v", value, err)
+		}
+		if enc.Value == value && value != "" {
+			t.Errorf("Encrypt(%q) left the value in the clear", value)
+		}
+		if !enc.Quoted {
+			t.Errorf("Encrypt(%q) dropped the Quoted flag", value)
+		}
+		dec, err := enc.Decrypt(key)
+		if err != nil {
+			t.Fatalf("Decrypt(%q) error: %v", enc.Value, err)
+		}
+		if dec != col {
+			t.Errorf("Decrypt(Encrypt(%q)) = %+v, want %+v", value, dec, col)
+		}
+	}
+
+	// Each encryption uses a fresh nonce.
+	a, _ := c("same").Encrypt(key)
+	b, _ := c("same").Encrypt(key)
+	if a.Value == b.Value {
+		t.Error("Encrypt() produced identical ciphertexts for the same value")
+	}
+
+	for _, badKey := range [][]byte{nil, make([]byte, 15), make([]byte, 33)} {
+		if _, err := c("x").Encrypt(badKey); err == nil {
+			t.Errorf("Encrypt() with %d byte key: expected error", len(badKey))
+		}
+		if _, err := a.Decrypt(badKey); err == nil {
+			t.Errorf("Decrypt() with %d byte key: expected error", len(badKey))
+		}
+	}
+
+	// Raw input captured by the Reader does not survive encryption.
+	r := NewReader(strings.NewReader("\"secret\"\n"))
+	r.CaptureRaw = true
+	rec, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	enc, err := rec[0].Encrypt(key)
+	if err != nil {
+		t.Fatalf("Encrypt() error: %v", err)
+	}
+	if enc.OriginalBytes != "" {
+		t.Errorf("Encrypt() kept OriginalBytes %q", enc.OriginalBytes)
+	}
+	enc.OriginalBytes = `"` + enc.Value + `"`
+	dec, err := enc.Decrypt(key)
+	if err != nil {
+		t.Fatalf("Decrypt() error: %v", err)
+	}
+	if want := q("secret"); dec != want {
+		t.Errorf("Decrypt() = %+v, want %+v", dec, want)
+	}
+
+	otherKey := bytes.Repeat([]byte{0x43}, 32)
+	for _, col := range []Column{a, c("not base64!"), c("c2hvcnQ=")} {
+		key := key
+		if col == a {
+			key = otherKey
+		}
+		if _, err := col.Decrypt(key); err != ErrInvalidCiphertext {
+			t.Errorf("Decrypt(%q) error = %v, want ErrInvalidCiphertext", col.Value, err)
+		}
+	}
+}
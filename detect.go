@@ -0,0 +1,223 @@
+package csv
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// sniffSize is the number of bytes DetectDelimiter inspects.
+const sniffSize = 8 << 10
+
+// delimiterCandidates are the delimiters DetectDelimiterFromSample chooses
+// from, in order of preference.
+var delimiterCandidates = []rune{',', ';', '\t', '|', ':'}
+
+// DetectDelimiter reads up to the first 8 KB of r and returns the delimiter
+// it most likely uses, as determined by DetectDelimiterFromSample.
+// It seeks r back to offset 0 before returning.
+func DetectDelimiter(r io.ReadSeeker) (rune, error) {
+	sample := make([]byte, sniffSize)
+	n, err := io.ReadFull(r, sample)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return 0, err
+	}
+	sample = sample[:n]
+	if n == sniffSize {
+		// The last line is likely cut short; only keep complete lines.
+		if i := bytes.LastIndexByte(sample, '\n'); i >= 0 {
+			sample = sample[:i+1]
+		}
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	return DetectDelimiterFromSample(sample), nil
+}
+
+// NewReaderAutoDelimiter returns a new Reader reading from r whose Comma is
+// set to the delimiter detected by DetectDelimiter.
+func NewReaderAutoDelimiter(r io.ReadSeeker) (*Reader, error) {
+	comma, err := DetectDelimiter(r)
+	if err != nil {
+		return nil, err
+	}
+	cr := NewReader(r)
+	cr.Comma = comma
+	return cr, nil
+}
+
+// DetectDelimiterFromSample returns the delimiter most likely used by the
+// CSV data in sample. Each of ',', ';', '\t', '|' and ':' is counted on
+// every non-blank line, ignoring occurrences inside quoted fields, and the
+// candidate that appears the same non-zero number of times on the most
+// lines wins. Ties go to the candidate appearing more often per line.
+// If there is no clear winner, DetectDelimiterFromSample returns ','.
+func DetectDelimiterFromSample(sample []byte) rune {
+	lines := sampleLines(sample)
+	if len(lines) == 0 {
+		return ','
+	}
+
+	best, bestLines, bestCount := ',', 0, 0
+	tied := false
+	for _, cand := range delimiterCandidates {
+		freq := make(map[int]int) // occurrences per line -> number of lines
+		for _, line := range lines {
+			freq[countUnquoted(line, cand)]++
+		}
+		count, nlines := 0, 0
+		for k, v := range freq {
+			if k > 0 && (v > nlines || v == nlines && k > count) {
+				count, nlines = k, v
+			}
+		}
+		if nlines == 0 {
+			continue
+		}
+		switch {
+		case nlines > bestLines || nlines == bestLines && count > bestCount:
+			best, bestLines, bestCount, tied = cand, nlines, count, false
+		case nlines == bestLines && count == bestCount:
+			tied = true
+		}
+	}
+	if tied {
+		return ','
+	}
+	return best
+}
+
+// sampleLines splits sample into its non-blank lines. Line breaks inside
+// quoted fields do not end a line.
+func sampleLines(sample []byte) [][]byte {
+	var lines [][]byte
+	quoted := false
+	start := 0
+	for i, b := range sample {
+		switch {
+		case b == '"':
+			quoted = !quoted
+		case b == '\n' && !quoted:
+			lines = appendLine(lines, sample[start:i])
+			start = i + 1
+		}
+	}
+	return appendLine(lines, sample[start:])
+}
+
+func appendLine(lines [][]byte, line []byte) [][]byte {
+	line = bytes.TrimSuffix(line, []byte{'\r'})
+	if len(line) == 0 {
+		return lines
+	}
+	return append(lines, line)
+}
+
+// countUnquoted reports the number of times the ASCII character delim occurs
+// in line outside of quoted fields.
+func countUnquoted(line []byte, delim rune) int {
+	n := 0
+	quoted := false
+	for _, b := range line {
+		switch {
+		case b == '"':
+			quoted = !quoted
+		case !quoted && rune(b) == delim:
+			n++
+		}
+	}
+	return n
+}
+
+// defaultHeaderDetectionThreshold is used by DetectHasHeader if
+// Reader.HeaderDetectionThreshold is not positive.
+const defaultHeaderDetectionThreshold = 0.5
+
+// DetectHasHeader reports whether the first record of r is likely a header
+// row. It peeks at the first sampleRows+1 records with Peek, so that they
+// are still returned by Read, and compares the first record to the others,
+// which are called data rows below. Each of the following is a vote for or
+// against a header:
+//
+//   - whether the first record holds only non-numeric values;
+//   - for each column whose data rows repeat values, whether the first
+//     value is missing from them;
+//   - for each column whose data rows have a type other than TypeString,
+//     as found by Table.InferTypes, whether the first value does not
+//     convert to it, and for other columns whose data rows all have the
+//     same length, whether the first value has a different length.
+//
+// DetectHasHeader returns true if the share of votes for a header exceeds
+// r.HeaderDetectionThreshold. Input without numeric columns, repeated
+// values or values of the same length gives little evidence, so a first
+// record of text is then taken to be a header. If there are no data rows,
+// DetectHasHeader returns false. Errors returned by Peek are returned.
+func (r *Reader) DetectHasHeader(sampleRows int) (bool, error) {
+	records, err := r.Peek(sampleRows + 1)
+	if err != nil {
+		return false, err
+	}
+	if len(records) < 2 {
+		return false, nil
+	}
+	first := records[0]
+	data := Table{Headers: make([]string, len(first)), Rows: records[1:]}
+	types := data.InferTypes()
+
+	var votes, total int
+	vote := func(header bool) {
+		total++
+		if header {
+			votes++
+		}
+	}
+
+	numeric := false
+	for _, col := range first {
+		if _, err := strconv.ParseFloat(strings.TrimSpace(col.Value), 64); err == nil {
+			numeric = true
+		}
+	}
+	vote(!numeric)
+
+	for i, col := range first {
+		seen := make(map[string]bool)
+		repeated := false
+		length, sameLength := -1, true
+		for _, record := range data.Rows {
+			if i >= len(record) || record[i].IsMissing() {
+				continue
+			}
+			v := record[i].Value
+			repeated = repeated || seen[v]
+			seen[v] = true
+			if n := utf8.RuneCountInString(v); length < 0 {
+				length = n
+			} else if n != length {
+				sameLength = false
+			}
+		}
+		if length < 0 {
+			continue // No data in this column
+		}
+		if repeated {
+			vote(!seen[col.Value])
+		}
+		if types[i] != TypeString {
+			cs := ColumnSchema{Type: types[i]}
+			_, err := cs.convert(col)
+			vote(err != nil)
+		} else if sameLength {
+			vote(utf8.RuneCountInString(col.Value) != length)
+		}
+	}
+
+	threshold := r.HeaderDetectionThreshold
+	if threshold <= 0 {
+		threshold = defaultHeaderDetectionThreshold
+	}
+	return float64(votes)/float64(total) > threshold, nil
+}
@@ -0,0 +1,161 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDetectDelimiterFromSample(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Want  rune
+	}{
+		{Name: "Comma", Input: "a,b,c\n1,2,3\n4,5,6\n", Want: ','},
+		{Name: "Semicolon", Input: "a;b;c\n1;2;3\n4;5;6\n", Want: ';'},
+		{Name: "Tab", Input: "a\tb\tc\n1\t2\t3\n", Want: '\t'},
+		{Name: "Pipe", Input: "a|b\n1|2\n3|4\n", Want: '|'},
+		{Name: "Colon", Input: "a:b:c\r\n1:2:3\r\n", Want: ':'},
+		{Name: "Empty", Input: "", Want: ','},
+		{Name: "SingleColumn", Input: "a\nb\nc\n", Want: ','},
+		{Name: "Tie", Input: "a;b|c\n1;2|3\n", Want: ','},
+		{
+			Name:  "DecimalCommas",
+			Input: "price;qty\n1,50;2\n2,25;10\n3;1\n",
+			Want:  ';',
+		},
+		{
+			Name:  "InconsistentMinority",
+			Input: "name;note\nAnn;x,y\nBob;z\nCid;w\n",
+			Want:  ';',
+		},
+		{
+			Name:  "QuotedAlternate",
+			Input: "a;\"b,c,d\"\n1;\"2,3,4\"\n5;6\n",
+			Want:  ';',
+		},
+		{
+			Name:  "QuotedNewline",
+			Input: "a|\"multi\nline, with, commas\"\n1|2\n",
+			Want:  '|',
+		},
+		{
+			Name:  "BlankLines",
+			Input: "\n\na\tb\n\n1\t2\n\n",
+			Want:  '\t',
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if got := DetectDelimiterFromSample([]byte(tt.Input)); got != tt.Want {
+				t.Errorf("DetectDelimiterFromSample() = %q, want %q", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestDetectDelimiterLargeInput(t *testing.T) {
+	// The sample ends in the middle of a line that has no delimiter at all.
+	input := strings.Repeat("aaaa;bbbb;cccc\n", sniffSize/15) + strings.Repeat("x", 100) + ";y\n"
+	r := strings.NewReader(input)
+	got, err := DetectDelimiter(r)
+	if err != nil {
+		t.Fatalf("DetectDelimiter() error: %v", err)
+	}
+	if got != ';' {
+		t.Errorf("DetectDelimiter() = %q, want ';'", got)
+	}
+	if off, _ := r.Seek(0, io.SeekCurrent); off != 0 {
+		t.Errorf("DetectDelimiter() left offset at %d, want 0", off)
+	}
+}
+
+func TestNewReaderAutoDelimiter(t *testing.T) {
+	r, err := NewReaderAutoDelimiter(strings.NewReader("name|lang\n\"Rob Pike\"|go\n"))
+	if err != nil {
+		t.Fatalf("NewReaderAutoDelimiter() error: %v", err)
+	}
+	if r.Comma != '|' {
+		t.Errorf("Comma = %q, want '|'", r.Comma)
+	}
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := [][]Column{{c("name"), c("lang")}, {q("Rob Pike"), c("go")}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAll() output:\ngot  %v\nwant %v", out, want)
+	}
+}
+
+func TestDetectHasHeader(t *testing.T) {
+	tests := []struct {
+		File string
+		Want bool
+	}{
+		{File: "products.csv", Want: true},
+		{File: "products-noheader.csv", Want: false},
+		{File: "countries.csv", Want: true},
+		{File: "countries-noheader.csv", Want: false},
+		{File: "cities.csv", Want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.File, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/header/" + tt.File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := NewReader(bytes.NewReader(data))
+			got, err := r.DetectHasHeader(10)
+			if err != nil {
+				t.Fatalf("DetectHasHeader() error: %v", err)
+			}
+			if got != tt.Want {
+				t.Errorf("DetectHasHeader() = %v, want %v", got, tt.Want)
+			}
+
+			// No records are consumed.
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			want, _ := NewReader(bytes.NewReader(data)).ReadAll()
+			if !reflect.DeepEqual(records, want) {
+				t.Errorf("ReadAll() after DetectHasHeader() = %v, want %v", records, want)
+			}
+		})
+	}
+}
+
+func TestDetectHasHeaderThreshold(t *testing.T) {
+	// One vote of two is for a header: the first record is not numeric,
+	// but its second value has the length of the data values.
+	const input = "Germany,DEU\nFrance,FRA\nSpain,ESP\n"
+	r := NewReader(strings.NewReader(input))
+	if got, _ := r.DetectHasHeader(5); got {
+		t.Errorf("DetectHasHeader() with default threshold = true, want false")
+	}
+	r = NewReader(strings.NewReader(input))
+	r.HeaderDetectionThreshold = 0.4
+	if got, _ := r.DetectHasHeader(5); !got {
+		t.Errorf("DetectHasHeader() with threshold 0.4 = false, want true")
+	}
+}
+
+func TestDetectHasHeaderShortInput(t *testing.T) {
+	for _, input := range []string{"", "a,b\n"} {
+		got, err := NewReader(strings.NewReader(input)).DetectHasHeader(5)
+		if got || err != nil {
+			t.Errorf("DetectHasHeader() of %q = %v, %v, want false, nil", input, got, err)
+		}
+	}
+	_, err := NewReader(strings.NewReader("a,b\n\"c\n")).DetectHasHeader(5)
+	if !errors.Is(err, ErrQuote) {
+		t.Errorf("DetectHasHeader() error = %v, want %v", err, ErrQuote)
+	}
+}
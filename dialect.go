@@ -0,0 +1,113 @@
+package csv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// A Dialect bundles the settings describing a CSV variant, so that they can
+// be applied to a Reader or Writer at once with SetDialect.
+type Dialect struct {
+	Comma   rune // Field delimiter
+	Quote   rune // Character enclosing quoted fields
+	Escape  rune // Escape character inside quoted fields, or 0 to double quotes
+	Comment rune // Comment character, or 0 for none
+	UseCRLF bool // True to use \r\n as the line terminator when writing
+
+	LazyQuotes       bool // See Reader.LazyQuotes
+	TrimLeadingSpace bool // See Reader.TrimLeadingSpace
+}
+
+// DialectRFC4180 returns the dialect described by RFC 4180:
+// comma-separated fields, doubled quotes and CRLF line terminators.
+func DialectRFC4180() Dialect {
+	return Dialect{Comma: ',', Quote: '"', UseCRLF: true}
+}
+
+// DialectExcel returns the dialect of CSV files saved by Microsoft Excel.
+func DialectExcel() Dialect {
+	return Dialect{Comma: ',', Quote: '"', UseCRLF: true}
+}
+
+// DialectMySQL returns the dialect of files written by MySQL's
+// SELECT ... INTO OUTFILE with FIELDS TERMINATED BY ',' ENCLOSED BY '"',
+// which escapes quotes with a backslash.
+func DialectMySQL() Dialect {
+	return Dialect{Comma: ',', Quote: '"', Escape: '\\'}
+}
+
+// DialectPostgresCOPY returns the dialect of PostgreSQL's COPY command
+// in CSV format with its default options.
+func DialectPostgresCOPY() Dialect {
+	return Dialect{Comma: ',', Quote: '"'}
+}
+
+// DialectTSV returns a dialect for tab-separated values.
+func DialectTSV() Dialect {
+	return Dialect{Comma: '\t', Quote: '"'}
+}
+
+// A DialectError is returned by Dialect.Validate and lists every
+// inconsistency found in the dialect.
+type DialectError struct {
+	Problems []string
+}
+
+func (e *DialectError) Error() string {
+	return "csv: invalid dialect: " + strings.Join(e.Problems, "; ")
+}
+
+// Validate reports whether d can be used by a Reader and a Writer.
+// If it cannot, Validate returns a *DialectError listing all violations.
+func (d Dialect) Validate() error {
+	var problems []string
+	if !validDelim(d.Comma) {
+		problems = append(problems, fmt.Sprintf("invalid Comma %q", d.Comma))
+	}
+	if !validQuote(d.Quote) {
+		problems = append(problems, fmt.Sprintf("invalid Quote %q", d.Quote))
+	} else if d.Quote == d.Comma {
+		problems = append(problems, "Quote equals Comma")
+	}
+	if d.Escape != 0 {
+		if !validQuote(d.Escape) {
+			problems = append(problems, fmt.Sprintf("invalid Escape %q", d.Escape))
+		} else if d.Escape == d.Comma {
+			problems = append(problems, "Escape equals Comma")
+		} else if d.Escape == d.Quote {
+			problems = append(problems, "Escape equals Quote; use 0 to double quotes")
+		}
+	}
+	if d.Comment != 0 {
+		if !validDelim(d.Comment) {
+			problems = append(problems, fmt.Sprintf("invalid Comment %q", d.Comment))
+		} else if d.Comment == d.Comma || d.Comment == d.Quote || d.Comment == d.Escape {
+			problems = append(problems, "Comment equals Comma, Quote or Escape")
+		}
+	}
+	if problems != nil {
+		return &DialectError{Problems: problems}
+	}
+	return nil
+}
+
+// SetDialect configures r for reading d.
+// d.UseCRLF only applies to writing and is ignored.
+func (r *Reader) SetDialect(d Dialect) {
+	r.Comma = d.Comma
+	r.Quote = d.Quote
+	r.EscapeChar = d.Escape
+	r.Comment = d.Comment
+	r.LazyQuotes = d.LazyQuotes
+	r.TrimLeadingSpace = d.TrimLeadingSpace
+}
+
+// SetDialect configures w for writing d.
+// d.LazyQuotes and d.TrimLeadingSpace only apply to reading and are ignored.
+func (w *Writer) SetDialect(d Dialect) {
+	w.Comma = d.Comma
+	w.Quote = d.Quote
+	w.EscapeChar = d.Escape
+	w.Comment = d.Comment
+	w.UseCRLF = d.UseCRLF
+}
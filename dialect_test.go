@@ -0,0 +1,128 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDialectRoundTrip(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Dialect Dialect
+		File    string
+		Records int
+	}{
+		{Name: "RFC4180", Dialect: DialectRFC4180(), File: "rfc4180.csv", Records: 4},
+		{Name: "Excel", Dialect: DialectExcel(), File: "excel.csv", Records: 4},
+		{Name: "MySQL", Dialect: DialectMySQL(), File: "mysql.csv", Records: 3},
+		{Name: "PostgresCOPY", Dialect: DialectPostgresCOPY(), File: "postgres.csv", Records: 3},
+		{Name: "TSV", Dialect: DialectTSV(), File: "tsv.csv", Records: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			if err := tt.Dialect.Validate(); err != nil {
+				t.Fatalf("Validate() error: %v", err)
+			}
+			data, err := os.ReadFile("testdata/dialect/" + tt.File)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r := NewReader(bytes.NewReader(data))
+			r.SetDialect(tt.Dialect)
+			records, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if len(records) != tt.Records {
+				t.Errorf("ReadAll() read %d records, want %d", len(records), tt.Records)
+			}
+			var b bytes.Buffer
+			w := NewWriter(&b)
+			w.SetDialect(tt.Dialect)
+			if err := w.WriteAll(records); err != nil {
+				t.Fatalf("WriteAll() error: %v", err)
+			}
+			if got := b.String(); got != string(data) {
+				t.Errorf("round trip:\ngot  %q\nwant %q", got, data)
+			}
+		})
+	}
+}
+
+func TestDialectMySQLEscape(t *testing.T) {
+	r := NewReader(strings.NewReader(`"O\"Brien",C:\temp` + "\n"))
+	r.SetDialect(DialectMySQL())
+	got, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if want := []Column{q(`O"Brien`), c(`C:\temp`)}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %v, want %v", got, want)
+	}
+}
+
+func TestDialectValidate(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Dialect  Dialect
+		Problems []string
+	}{
+		{Name: "Valid", Dialect: Dialect{Comma: ';', Quote: '\'', Escape: '\\', Comment: '#'}},
+		{Name: "QuoteEqualsComma", Dialect: Dialect{Comma: '|', Quote: '|'}, Problems: []string{"Quote equals Comma"}},
+		{Name: "NoQuote", Dialect: Dialect{Comma: ','}, Problems: []string{"invalid Quote '\\x00'"}},
+		{Name: "EscapeEqualsQuote", Dialect: Dialect{Comma: ',', Quote: '"', Escape: '"'}, Problems: []string{"Escape equals Quote; use 0 to double quotes"}},
+		{Name: "Many", Dialect: Dialect{Comma: '\n', Quote: '\r', Escape: '\r', Comment: '\n'}, Problems: []string{
+			"invalid Comma '\\n'",
+			"invalid Quote '\\r'",
+			"invalid Escape '\\r'",
+			"invalid Comment '\\n'",
+		}},
+		{Name: "CommentEqualsEscape", Dialect: Dialect{Comma: ',', Quote: '\'', Escape: '#', Comment: '#'}, Problems: []string{"Comment equals Comma, Quote or Escape"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			err := tt.Dialect.Validate()
+			if tt.Problems == nil {
+				if err != nil {
+					t.Fatalf("Validate() error: %v", err)
+				}
+				return
+			}
+			var de *DialectError
+			if !errors.As(err, &de) {
+				t.Fatalf("Validate() error = %v, want *DialectError", err)
+			}
+			if !reflect.DeepEqual(de.Problems, tt.Problems) {
+				t.Errorf("Validate() problems:\ngot  %q\nwant %q", de.Problems, tt.Problems)
+			}
+		})
+	}
+}
+
+func TestDialectQuote(t *testing.T) {
+	d := Dialect{Comma: ';', Quote: '\'', Escape: '\\'}
+	var b strings.Builder
+	w := NewWriter(&b)
+	w.SetDialect(d)
+	records := [][]Column{{c("it's"), c("a;b"), c(`"x"`)}}
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	if got, want := b.String(), `'it\'s';'a;b';"x"`+"\n"; got != want {
+		t.Errorf("WriteAll() = %q, want %q", got, want)
+	}
+	r := NewReader(strings.NewReader(b.String()))
+	r.SetDialect(d)
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := [][]Column{{q("it's"), q("a;b"), c(`"x"`)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,300 @@
+package csv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// A ColumnDiff describes the differences between two versions of a Column.
+type ColumnDiff struct {
+	ValueChanged  bool
+	QuotedChanged bool
+	OldValue      string
+	NewValue      string
+	OldQuoted     bool
+	NewQuoted     bool
+}
+
+// IsZero reports whether d records no change.
+func (d ColumnDiff) IsZero() bool {
+	return !d.ValueChanged && !d.QuotedChanged
+}
+
+// Diff compares c to a newer version other.
+func (c Column) Diff(other Column) ColumnDiff {
+	return ColumnDiff{
+		ValueChanged:  c.Value != other.Value,
+		QuotedChanged: c.Quoted != other.Quoted,
+		OldValue:      c.Value,
+		NewValue:      other.Value,
+		OldQuoted:     c.Quoted,
+		NewQuoted:     other.Quoted,
+	}
+}
+
+// RowDiff compares record a to a newer version b column by column.
+// The result has one entry per column of the longer record;
+// columns missing from the shorter record are compared as empty columns.
+func RowDiff(a, b []Column) []ColumnDiff {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	diffs := make([]ColumnDiff, n)
+	for i := range diffs {
+		var oldCol, newCol Column
+		if i < len(a) {
+			oldCol = a[i]
+		}
+		if i < len(b) {
+			newCol = b[i]
+		}
+		diffs[i] = oldCol.Diff(newCol)
+	}
+	return diffs
+}
+
+// A DiffOp is the kind of change recorded in a RecordDiff.
+type DiffOp int
+
+const (
+	DiffUnchanged DiffOp = iota // The record is the same in both versions
+	DiffAdded                   // The record only exists in the new version
+	DiffRemoved                 // The record only exists in the old version
+	DiffModified                // Some fields of the record changed
+)
+
+func (op DiffOp) String() string {
+	switch op {
+	case DiffUnchanged:
+		return "unchanged"
+	case DiffAdded:
+		return "added"
+	case DiffRemoved:
+		return "removed"
+	case DiffModified:
+		return "modified"
+	}
+	return fmt.Sprintf("DiffOp(%d)", int(op))
+}
+
+// A FieldDiff describes a field that differs between two versions of a record.
+type FieldDiff struct {
+	Index     int    // Index of the field in the record
+	OldColumn Column // Field in the old version, or zero if missing
+	NewColumn Column // Field in the new version, or zero if missing
+}
+
+// A RecordDiff describes how a record changed between two versions of a file.
+type RecordDiff struct {
+	Op DiffOp
+
+	// RowIndex is the index of the record in the new version,
+	// or in the old version if Op is DiffRemoved.
+	RowIndex int
+
+	Old []Column // Record in the old version, nil if Op is DiffAdded
+	New []Column // Record in the new version, nil if Op is DiffRemoved
+
+	// Fields lists the differing fields if Op is DiffModified.
+	Fields []FieldDiff
+}
+
+// DiffOptions control how Diff matches and compares records.
+type DiffOptions struct {
+	// KeyColumns are the indexes of the columns identifying a record.
+	// Records of both versions with equal keys are compared with each other.
+	// If KeyColumns is empty, records are matched by their position.
+	KeyColumns []int
+
+	IgnoreSpace  bool // Ignore leading and trailing space and runs of white space
+	IgnoreCase   bool // Compare values case-insensitively
+	IgnoreQuoted bool // Ignore changes of the Quoted flag
+}
+
+// Diff compares the records of a to those of a newer version b and returns
+// one RecordDiff for every record of either version. Records are listed
+// in the order of b, with each removed record placed before the first
+// following record that also exists in b. A nil opts uses the zero
+// DiffOptions. Missing fields at the end of a record compare as empty
+// columns. If several records share a key, they are matched in order.
+func Diff(a, b [][]Column, opts *DiffOptions) []RecordDiff {
+	if opts == nil {
+		opts = &DiffOptions{}
+	}
+	// match[j] is the index in a of the record matching b[j], or -1.
+	match := make([]int, len(b))
+	if len(opts.KeyColumns) == 0 {
+		for j := range match {
+			match[j] = -1
+			if j < len(a) {
+				match[j] = j
+			}
+		}
+	} else {
+		byKey := make(map[string][]int)
+		for i, record := range a {
+			k := opts.key(record)
+			byKey[k] = append(byKey[k], i)
+		}
+		for j, record := range b {
+			match[j] = -1
+			k := opts.key(record)
+			if idx := byKey[k]; len(idx) > 0 {
+				match[j] = idx[0]
+				byKey[k] = idx[1:]
+			}
+		}
+	}
+
+	matched := make([]bool, len(a))
+	for _, i := range match {
+		if i >= 0 {
+			matched[i] = true
+		}
+	}
+	var diffs []RecordDiff
+	next := 0 // First record of a not yet considered for removal
+	removeUpTo := func(n int) {
+		for ; next < n; next++ {
+			if !matched[next] {
+				diffs = append(diffs, RecordDiff{Op: DiffRemoved, RowIndex: next, Old: a[next]})
+			}
+		}
+	}
+	for j, i := range match {
+		if i < 0 {
+			diffs = append(diffs, RecordDiff{Op: DiffAdded, RowIndex: j, New: b[j]})
+			continue
+		}
+		removeUpTo(i)
+		d := RecordDiff{Op: DiffUnchanged, RowIndex: j, Old: a[i], New: b[j]}
+		if d.Fields = opts.fieldDiffs(a[i], b[j]); d.Fields != nil {
+			d.Op = DiffModified
+		}
+		diffs = append(diffs, d)
+	}
+	removeUpTo(len(a))
+	return diffs
+}
+
+// key returns the key of record under o.
+func (o *DiffOptions) key(record []Column) string {
+	var b strings.Builder
+	for _, k := range o.KeyColumns {
+		if k < len(record) {
+			b.WriteString(o.normalize(record[k].Value))
+		}
+		b.WriteByte(0)
+	}
+	return b.String()
+}
+
+// normalize returns the form of value compared under o.
+func (o *DiffOptions) normalize(value string) string {
+	if o.IgnoreSpace {
+		value = strings.Join(strings.Fields(value), " ")
+	}
+	if o.IgnoreCase {
+		value = strings.ToLower(value)
+	}
+	return value
+}
+
+// fieldDiffs returns the fields differing between a and b under o,
+// or nil if there are none.
+func (o *DiffOptions) fieldDiffs(a, b []Column) []FieldDiff {
+	n := len(a)
+	if len(b) > n {
+		n = len(b)
+	}
+	var fields []FieldDiff
+	for i := 0; i < n; i++ {
+		var oldCol, newCol Column
+		if i < len(a) {
+			oldCol = a[i]
+		}
+		if i < len(b) {
+			newCol = b[i]
+		}
+		if oldCol.IsNull == newCol.IsNull &&
+			o.normalize(oldCol.Value) == o.normalize(newCol.Value) &&
+			(o.IgnoreQuoted || oldCol.Quoted == newCol.Quoted) {
+			continue
+		}
+		fields = append(fields, FieldDiff{Index: i, OldColumn: oldCol, NewColumn: newCol})
+	}
+	return fields
+}
+
+// A DiffRenderer writes a representation of the result of Diff to w.
+type DiffRenderer interface {
+	RenderDiff(w io.Writer, diffs []RecordDiff) error
+}
+
+// ANSI escape sequences used by TextDiffRenderer.
+const (
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiReset  = "\x1b[0m"
+)
+
+// A TextDiffRenderer renders a diff as text, one line per record in the
+// style of a unified diff: added records are prefixed with "+", removed
+// records with "-", and modified records with "~" followed by one line
+// per changed field.
+type TextDiffRenderer struct {
+	Comma         rune // Field delimiter used to print records; ',' if 0
+	Color         bool // Color lines using ANSI escape sequences
+	ShowUnchanged bool // Also print unchanged records, prefixed with " "
+}
+
+// RenderDiff implements DiffRenderer.
+func (t TextDiffRenderer) RenderDiff(w io.Writer, diffs []RecordDiff) error {
+	comma := t.Comma
+	if comma == 0 {
+		comma = ','
+	}
+	format := func(record []Column) string {
+		fields := make([]string, len(record))
+		for i, col := range record {
+			fields[i] = col.AsCSV(comma)
+		}
+		return strings.Join(fields, string(comma))
+	}
+	line := func(color, text string) error {
+		if t.Color && color != "" {
+			text = color + text + ansiReset
+		}
+		_, err := io.WriteString(w, text+"\n")
+		return err
+	}
+	for _, d := range diffs {
+		var err error
+		switch d.Op {
+		case DiffUnchanged:
+			if t.ShowUnchanged {
+				err = line("", "  "+format(d.New))
+			}
+		case DiffAdded:
+			err = line(ansiGreen, "+ "+format(d.New))
+		case DiffRemoved:
+			err = line(ansiRed, "- "+format(d.Old))
+		case DiffModified:
+			err = line(ansiYellow, "~ "+format(d.New))
+			for _, f := range d.Fields {
+				if err != nil {
+					break
+				}
+				err = line(ansiYellow, fmt.Sprintf("    field %d: %s -> %s",
+					f.Index, f.OldColumn.AsCSV(comma), f.NewColumn.AsCSV(comma)))
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package csv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRowDiff(t *testing.T) {
+	before := "id,name,city\n1,Rob,Sydney\n2,Ken,\"New Jersey\"\n"
+	after := "id,name,city\n1,Robert,Sydney\n2,Ken,New Jersey\n"
+
+	readAll := func(s string) [][]Column {
+		records, err := NewReader(strings.NewReader(s)).ReadAll()
+		if err != nil {
+			t.Fatalf("ReadAll() error: %v", err)
+		}
+		return records
+	}
+	a, b := readAll(before), readAll(after)
+
+	type cell struct{ row, col int }
+	changed := map[cell]ColumnDiff{
+		{1, 1}: {ValueChanged: true, OldValue: "Rob", NewValue: "Robert"},
+		{2, 2}: {QuotedChanged: true, OldValue: "New Jersey", NewValue: "New Jersey", OldQuoted: true},
+	}
+	for i := range a {
+		for j, d := range RowDiff(a[i], b[i]) {
+			want, ok := changed[cell{i, j}]
+			if !ok {
+				if !d.IsZero() {
+					t.Errorf("row %d, column %d: unexpected change %+v", i, j, d)
+				}
+				continue
+			}
+			if d != want {
+				t.Errorf("row %d, column %d: got %+v, want %+v", i, j, d, want)
+			}
+		}
+	}
+
+	diffs := RowDiff([]Column{c("a")}, []Column{c("a"), q("b")})
+	if len(diffs) != 2 || !diffs[0].IsZero() || !diffs[1].ValueChanged || !diffs[1].QuotedChanged {
+		t.Errorf("RowDiff() with added column = %+v", diffs)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := [][]Column{
+		{c("1"), c("Rob"), c("Sydney")},
+		{c("2"), c("Ken"), q("New Jersey")},
+		{c("3"), c("Russ"), c("Boston")},
+		{c("4"), c("Ian"), c("")},
+	}
+	b := [][]Column{
+		{c("2"), c("ken"), c("New  Jersey ")},
+		{c("1"), c("Robert"), c("Sydney")},
+		{c("5"), c("Andrew"), c("Sydney")},
+		{c(" 4"), c("Ian")},
+	}
+	tests := []struct {
+		Name string
+		Opts *DiffOptions
+		Want []RecordDiff
+	}{{
+		Name: "Position",
+		Want: []RecordDiff{
+			{Op: DiffModified, RowIndex: 0, Old: a[0], New: b[0], Fields: []FieldDiff{
+				{0, c("1"), c("2")}, {1, c("Rob"), c("ken")}, {2, c("Sydney"), c("New  Jersey ")},
+			}},
+			{Op: DiffModified, RowIndex: 1, Old: a[1], New: b[1], Fields: []FieldDiff{
+				{0, c("2"), c("1")}, {1, c("Ken"), c("Robert")}, {2, q("New Jersey"), c("Sydney")},
+			}},
+			{Op: DiffModified, RowIndex: 2, Old: a[2], New: b[2], Fields: []FieldDiff{
+				{0, c("3"), c("5")}, {1, c("Russ"), c("Andrew")}, {2, c("Boston"), c("Sydney")},
+			}},
+			{Op: DiffModified, RowIndex: 3, Old: a[3], New: b[3], Fields: []FieldDiff{
+				{0, c("4"), c(" 4")},
+			}},
+		},
+	}, {
+		Name: "Key",
+		Opts: &DiffOptions{KeyColumns: []int{0}},
+		Want: []RecordDiff{
+			{Op: DiffModified, RowIndex: 0, Old: a[1], New: b[0], Fields: []FieldDiff{
+				{1, c("Ken"), c("ken")}, {2, q("New Jersey"), c("New  Jersey ")},
+			}},
+			{Op: DiffModified, RowIndex: 1, Old: a[0], New: b[1], Fields: []FieldDiff{
+				{1, c("Rob"), c("Robert")},
+			}},
+			{Op: DiffAdded, RowIndex: 2, New: b[2]},
+			{Op: DiffAdded, RowIndex: 3, New: b[3]},
+			{Op: DiffRemoved, RowIndex: 2, Old: a[2]},
+			{Op: DiffRemoved, RowIndex: 3, Old: a[3]},
+		},
+	}, {
+		Name: "IgnoreAll",
+		Opts: &DiffOptions{KeyColumns: []int{0}, IgnoreSpace: true, IgnoreCase: true, IgnoreQuoted: true},
+		Want: []RecordDiff{
+			{Op: DiffUnchanged, RowIndex: 0, Old: a[1], New: b[0]},
+			{Op: DiffModified, RowIndex: 1, Old: a[0], New: b[1], Fields: []FieldDiff{
+				{1, c("Rob"), c("Robert")},
+			}},
+			{Op: DiffAdded, RowIndex: 2, New: b[2]},
+			{Op: DiffRemoved, RowIndex: 2, Old: a[2]},
+			{Op: DiffUnchanged, RowIndex: 3, Old: a[3], New: b[3]},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := Diff(a, b, tt.Opts)
+			if !reflect.DeepEqual(got, tt.Want) {
+				t.Errorf("Diff():\ngot  %+v\nwant %+v", got, tt.Want)
+			}
+		})
+	}
+}
+
+func TestTextDiffRenderer(t *testing.T) {
+	a := [][]Column{{c("1"), c("a")}, {c("2"), c("b")}, {c("3"), c("c")}}
+	b := [][]Column{{c("1"), c("a")}, {c("2"), q("b,c")}, {c("4"), c("d")}}
+	diffs := Diff(a, b, &DiffOptions{KeyColumns: []int{0}})
+
+	var r DiffRenderer = TextDiffRenderer{ShowUnchanged: true}
+	var sb strings.Builder
+	if err := r.RenderDiff(&sb, diffs); err != nil {
+		t.Fatalf("RenderDiff() error: %v", err)
+	}
+	want := "  1,a\n" +
+		"~ 2,\"b,c\"\n" +
+		"    field 1: b -> \"b,c\"\n" +
+		"+ 4,d\n" +
+		"- 3,c\n"
+	if got := sb.String(); got != want {
+		t.Errorf("RenderDiff():\ngot  %q\nwant %q", got, want)
+	}
+
+	sb.Reset()
+	r = TextDiffRenderer{Comma: ';', Color: true}
+	if err := r.RenderDiff(&sb, diffs); err != nil {
+		t.Fatalf("RenderDiff() error: %v", err)
+	}
+	want = "\x1b[33m~ 2;\"b,c\"\x1b[0m\n" +
+		"\x1b[33m    field 1: b -> \"b,c\"\x1b[0m\n" +
+		"\x1b[32m+ 4;d\x1b[0m\n" +
+		"\x1b[31m- 3;c\x1b[0m\n"
+	if got := sb.String(); got != want {
+		t.Errorf("RenderDiff() with Color:\ngot  %q\nwant %q", got, want)
+	}
+}
@@ -0,0 +1,204 @@
+package csv
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// A RecoveryStrategy is a way in which a FuzzyReader recovers from a
+// ParseError. The strategies are bit flags, as several may be applied to
+// the same record.
+type RecoveryStrategy int
+
+const (
+	// RecoverLazyQuotes re-parses the first line of the record in error
+	// with LazyQuotes set. Any further lines the record spanned are parsed
+	// again as the following records.
+	RecoverLazyQuotes RecoveryStrategy = 1 << iota
+
+	// RecoverFieldCount adds empty fields to a record that has fewer than
+	// FieldsPerRecord fields, or joins the surplus fields of a record
+	// that has too many into its last field, separated by Comma.
+	RecoverFieldCount
+
+	// RecoverDiscard skips the record.
+	RecoverDiscard
+)
+
+func (s RecoveryStrategy) String() string {
+	var names []string
+	for _, f := range []struct {
+		flag RecoveryStrategy
+		name string
+	}{
+		{RecoverLazyQuotes, "lazy quotes"},
+		{RecoverFieldCount, "field count"},
+		{RecoverDiscard, "discard"},
+	} {
+		if s&f.flag != 0 {
+			names = append(names, f.name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// A RecoveryAction records how a FuzzyReader recovered from a ParseError.
+type RecoveryAction struct {
+	Err      *ParseError      // The error recovered from
+	Strategy RecoveryStrategy // The strategies applied
+	Record   []Column         // The recovered record, or nil if it was discarded
+}
+
+// A FuzzyReader reads records like a Reader, but tries to recover from
+// ParseErrors caused by common defects of hand-made or badly exported
+// files, such as stray quotes, unterminated quoted fields and unescaped
+// delimiters. On a ParseError it tries the following in order:
+//
+//   - If the record could not be parsed, its first line is parsed again
+//     with LazyQuotes set (RecoverLazyQuotes).
+//   - If FieldsPerRecord is positive and the record has a different
+//     number of fields, they are adjusted to match (RecoverFieldCount).
+//   - If the record still cannot be used, it is skipped (RecoverDiscard).
+//
+// Each recovery is appended to RecoveryLog. This is a best-effort
+// heuristic: a recovered record may not hold the data the author of the
+// file intended, and should be checked against RecoveryLog.
+type FuzzyReader struct {
+	// MaxRecoveryAttempts is the number of ParseErrors the FuzzyReader
+	// recovers from. Once it is reached, further ParseErrors are
+	// returned. It is set to 100 by NewFuzzyReader.
+	MaxRecoveryAttempts int
+
+	// RecoveryLog lists the recoveries made so far, in order.
+	RecoveryLog []RecoveryAction
+
+	r *Reader
+
+	// pending parses the lines of a recovered record after its first
+	// line, before reading on from r. Its line numbers start after
+	// pendingBase.
+	pending     *Reader
+	pendingBase int
+}
+
+// NewFuzzyReader returns a FuzzyReader reading from r. It sets
+// r.CaptureRaw, which it needs to parse records in error again, so the
+// returned Columns have OriginalBytes set.
+func NewFuzzyReader(r *Reader) *FuzzyReader {
+	r.CaptureRaw = true
+	return &FuzzyReader{MaxRecoveryAttempts: 100, r: r}
+}
+
+// Read reads one record like Reader.Read, recovering from ParseErrors
+// as described for FuzzyReader. Line numbers in returned errors refer to
+// the whole input.
+func (f *FuzzyReader) Read() ([]Column, error) {
+	for {
+		src, base := f.r, 0
+		if f.pending != nil {
+			src, base = f.pending, f.pendingBase
+		}
+		fields := src.FieldsPerRecord
+		record, err := src.Read()
+		if err == io.EOF && src == f.pending {
+			f.pending = nil
+			continue
+		}
+		perr, ok := err.(*ParseError)
+		if !ok {
+			return record, err
+		}
+		perr = adjustLines(perr, base).(*ParseError)
+		if len(f.RecoveryLog) >= f.MaxRecoveryAttempts {
+			return record, perr
+		}
+
+		action := RecoveryAction{Err: perr}
+		if perr.Err != ErrFieldCount {
+			// Parse the first line again, and the others afterwards.
+			raw := src.rawRecord
+			n := bytes.IndexByte(raw, '\n') + 1
+			if n == 0 {
+				n = len(raw)
+			}
+			record = f.parseLazy(raw[:n])
+			action.Strategy |= RecoverLazyQuotes
+			// Read sets FieldsPerRecord from the record in error if it
+			// was 0; set it from the recovered record instead.
+			f.r.FieldsPerRecord = fields
+			if fields == 0 && record != nil {
+				f.r.FieldsPerRecord = len(record)
+			}
+			if f.pending != nil {
+				f.pending.FieldsPerRecord = f.r.FieldsPerRecord
+			}
+			if rest := raw[n:]; len(rest) > 0 {
+				in := io.Reader(bytes.NewReader(append([]byte(nil), rest...)))
+				if src == f.pending {
+					in = io.MultiReader(in, src.r)
+				}
+				f.pending = f.r.Clone(in)
+				f.pendingBase = perr.StartLine
+			}
+		}
+		if n := fields; record != nil && n > 0 && len(record) != n {
+			record = f.fixFieldCount(record, n)
+			action.Strategy |= RecoverFieldCount
+		}
+		if record == nil {
+			action.Strategy |= RecoverDiscard
+		}
+		action.Record = record
+		f.RecoveryLog = append(f.RecoveryLog, action)
+		if record != nil {
+			return record, nil
+		}
+	}
+}
+
+// ReadAll reads all the remaining records like Reader.ReadAll, recovering
+// from ParseErrors as described for FuzzyReader.
+func (f *FuzzyReader) ReadAll() (records [][]Column, err error) {
+	for {
+		record, err := f.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+}
+
+// parseLazy parses line with the settings of f.r and LazyQuotes set.
+// It returns nil if line does not hold a record.
+func (f *FuzzyReader) parseLazy(line []byte) []Column {
+	lr := f.r.Clone(bytes.NewReader(line))
+	lr.LazyQuotes = true
+	lr.FieldsPerRecord = -1
+	lr.SetSchema(nil)
+	lr.SetErrorHandler(nil)
+	record, err := lr.Read()
+	if err != nil {
+		return nil
+	}
+	return record
+}
+
+// fixFieldCount returns record with n fields, adding empty fields or
+// joining the surplus ones into the last field.
+func (f *FuzzyReader) fixFieldCount(record []Column, n int) []Column {
+	if len(record) < n {
+		return append(record, make([]Column, n-len(record))...)
+	}
+	values := make([]string, 0, len(record)-n+1)
+	for _, col := range record[n-1:] {
+		values = append(values, col.Value)
+	}
+	last := record[n-1]
+	last.Value = strings.Join(values, string(f.r.Comma))
+	last.OriginalBytes = ""
+	return append(record[:n-1:n-1], last)
+}
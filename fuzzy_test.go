@@ -0,0 +1,185 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fuzzyValues returns the values of records, for comparing them.
+func fuzzyValues(records [][]Column) [][]string {
+	out := make([][]string, len(records))
+	for i, record := range records {
+		for _, col := range record {
+			out[i] = append(out[i], col.Value)
+		}
+	}
+	return out
+}
+
+// TestFuzzyReaderFalsePositives checks that a FuzzyReader makes no
+// recoveries on valid input and reads the same records as a Reader.
+func TestFuzzyReaderFalsePositives(t *testing.T) {
+	type input struct {
+		Name  string
+		Data  []byte
+		Setup func(r *Reader)
+	}
+	var inputs []input
+	for _, tt := range []struct {
+		File    string
+		Dialect Dialect
+	}{
+		{"rfc4180.csv", DialectRFC4180()},
+		{"excel.csv", DialectExcel()},
+		{"mysql.csv", DialectMySQL()},
+		{"postgres.csv", DialectPostgresCOPY()},
+		{"tsv.csv", DialectTSV()},
+	} {
+		data, err := os.ReadFile("testdata/dialect/" + tt.File)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := tt.Dialect
+		inputs = append(inputs, input{tt.File, data, func(r *Reader) { r.SetDialect(d) }})
+	}
+	data, err := os.ReadFile("testdata/fs.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	inputs = append(inputs, input{"fs.csv", data, func(*Reader) {}})
+	inputs = append(inputs, input{"parallelInput", []byte(parallelInput(500)), func(r *Reader) {
+		r.Comment = '#'
+		r.StripBOM = true
+	}})
+
+	var records, recoveries int
+	for _, in := range inputs {
+		r := NewReader(bytes.NewReader(in.Data))
+		in.Setup(r)
+		want, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("%s: Reader.ReadAll() error: %v", in.Name, err)
+		}
+		fr := NewReader(bytes.NewReader(in.Data))
+		in.Setup(fr)
+		f := NewFuzzyReader(fr)
+		got, err := f.ReadAll()
+		if err != nil {
+			t.Fatalf("%s: FuzzyReader.ReadAll() error: %v", in.Name, err)
+		}
+		if !reflect.DeepEqual(fuzzyValues(got), fuzzyValues(want)) {
+			t.Errorf("%s: FuzzyReader.ReadAll() records differ from Reader.ReadAll()", in.Name)
+		}
+		records += len(got)
+		recoveries += len(f.RecoveryLog)
+	}
+	if recoveries != 0 {
+		t.Errorf("%d recoveries in %d valid records, want 0", recoveries, records)
+	}
+}
+
+func TestFuzzyReader(t *testing.T) {
+	tests := []struct {
+		Name            string
+		Input           string
+		FieldsPerRecord int
+		MaxFieldSize    int
+		Output          [][]string
+		Strategies      []RecoveryStrategy
+		Lines           []int
+	}{{
+		Name:       "BareQuote",
+		Input:      "a,b\"c,d\ne,f,g\n",
+		Output:     [][]string{{"a", "b\"c", "d"}, {"e", "f", "g"}},
+		Strategies: []RecoveryStrategy{RecoverLazyQuotes},
+		Lines:      []int{1},
+	}, {
+		Name:       "MissingClosingQuote",
+		Input:      "a,\"b\nc,d\ne,f\n",
+		Output:     [][]string{{"a", "b\n"}, {"c", "d"}, {"e", "f"}},
+		Strategies: []RecoveryStrategy{RecoverLazyQuotes},
+		Lines:      []int{1},
+	}, {
+		Name:            "MissingClosingQuoteFieldCount",
+		Input:           "a,\"b,c\nd,e,f\ng,h,i\n",
+		FieldsPerRecord: 3,
+		Output:          [][]string{{"a", "b,c\n", ""}, {"d", "e", "f"}, {"g", "h", "i"}},
+		Strategies:      []RecoveryStrategy{RecoverLazyQuotes | RecoverFieldCount},
+		Lines:           []int{1},
+	}, {
+		Name:       "ErrorInRecoveredLines",
+		Input:      "a,\"b\nc,d\"e\nf,g\n",
+		Output:     [][]string{{"a", "b\n"}, {"c", "d\"e"}, {"f", "g"}},
+		Strategies: []RecoveryStrategy{RecoverLazyQuotes, RecoverLazyQuotes},
+		Lines:      []int{1, 2},
+	}, {
+		Name:       "ExtraDelimiter",
+		Input:      "a,b,c\nd,e,f,g\nh,i,j\n",
+		Output:     [][]string{{"a", "b", "c"}, {"d", "e", "f,g"}, {"h", "i", "j"}},
+		Strategies: []RecoveryStrategy{RecoverFieldCount},
+		Lines:      []int{2},
+	}, {
+		Name:       "TooFewFields",
+		Input:      "a,b,c\nd\n",
+		Output:     [][]string{{"a", "b", "c"}, {"d", "", ""}},
+		Strategies: []RecoveryStrategy{RecoverFieldCount},
+		Lines:      []int{2},
+	}, {
+		Name:         "Discard",
+		Input:        "a,b\nc,d\"efgh\ni,j\n",
+		MaxFieldSize: 4,
+		Output:       [][]string{{"a", "b"}, {"i", "j"}},
+		Strategies:   []RecoveryStrategy{RecoverLazyQuotes | RecoverDiscard},
+		Lines:        []int{2},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			r.FieldsPerRecord = tt.FieldsPerRecord
+			r.MaxFieldSize = tt.MaxFieldSize
+			f := NewFuzzyReader(r)
+			records, err := f.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if got := fuzzyValues(records); !reflect.DeepEqual(got, tt.Output) {
+				t.Errorf("ReadAll() = %q, want %q", got, tt.Output)
+			}
+			var strategies []RecoveryStrategy
+			var lines []int
+			for _, a := range f.RecoveryLog {
+				strategies = append(strategies, a.Strategy)
+				lines = append(lines, a.Err.StartLine)
+			}
+			if !reflect.DeepEqual(strategies, tt.Strategies) {
+				t.Errorf("RecoveryLog strategies = %v, want %v", strategies, tt.Strategies)
+			}
+			if !reflect.DeepEqual(lines, tt.Lines) {
+				t.Errorf("RecoveryLog lines = %v, want %v", lines, tt.Lines)
+			}
+		})
+	}
+}
+
+func TestFuzzyReaderMaxRecoveryAttempts(t *testing.T) {
+	f := NewFuzzyReader(NewReader(strings.NewReader("a,b\nc\nd\ne\n")))
+	f.MaxRecoveryAttempts = 1
+	_, err := f.ReadAll()
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Err != ErrFieldCount || perr.Line != 3 {
+		t.Fatalf("ReadAll() error = %v, want ErrFieldCount on line 3", err)
+	}
+	if len(f.RecoveryLog) != 1 {
+		t.Errorf("len(RecoveryLog) = %d, want 1", len(f.RecoveryLog))
+	}
+}
+
+func TestRecoveryStrategyString(t *testing.T) {
+	if got, want := (RecoverLazyQuotes | RecoverDiscard).String(), "lazy quotes, discard"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
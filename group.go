@@ -0,0 +1,173 @@
+package csv
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// GroupBy splits the rows of t by their value in the column called col.
+// The returned map holds one Table per distinct value, with the same
+// Headers as t and the rows having that value, in their order in t.
+// Missing fields group as the empty value. The rows of the returned
+// Tables share their fields with t.
+func (t *Table) GroupBy(col string) (map[string]Table, error) {
+	i, err := t.index(col)
+	if err != nil {
+		return nil, err
+	}
+	groups := make(map[string]Table)
+	for _, record := range t.Rows {
+		var key string
+		if i < len(record) {
+			key = record[i].Value
+		}
+		g, ok := groups[key]
+		if !ok {
+			g = Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+		}
+		g.Rows = append(g.Rows, record)
+		groups[key] = g
+	}
+	return groups, nil
+}
+
+// An AggFunc is a function computing an aggregate of the values of a
+// column, used in an AggSpec.
+type AggFunc int
+
+const (
+	AggCount AggFunc = iota // Number of values
+	AggSum                  // Sum of the values
+	AggMin                  // Smallest value
+	AggMax                  // Largest value
+	AggMean                 // Arithmetic mean of the values
+)
+
+func (f AggFunc) String() string {
+	switch f {
+	case AggCount:
+		return "count"
+	case AggSum:
+		return "sum"
+	case AggMin:
+		return "min"
+	case AggMax:
+		return "max"
+	case AggMean:
+		return "mean"
+	}
+	return "AggFunc(" + strconv.Itoa(int(f)) + ")"
+}
+
+// An AggSpec describes a column computed by Table.Aggregate.
+type AggSpec struct {
+	Column string  // Name of the column aggregated
+	Func   AggFunc // Aggregate function
+
+	// Name is the name of the computed column. If empty, the name is
+	// Func and Column as in "sum(price)".
+	Name string
+}
+
+// Aggregate groups the rows of t by their value in the column called
+// groupCol and computes the aggregates described by aggs for each group.
+// The returned Table has a column named groupCol followed by one column
+// per AggSpec, and one row per group, in order of first appearance.
+//
+// Like SQL aggregates, the functions ignore missing values (see
+// Column.IsMissing). AggSum, AggMin, AggMax and AggMean require the
+// other values to be numbers, or Aggregate returns an error. If a group
+// has no values to aggregate, its AggCount is 0 and the other aggregates
+// are empty. Results are formatted with strconv.FormatFloat using the
+// 'g' format and the smallest precision representing them exactly.
+func (t *Table) Aggregate(groupCol string, aggs []AggSpec) (Table, error) {
+	g, err := t.index(groupCol)
+	if err != nil {
+		return Table{}, err
+	}
+	out := Table{Headers: []string{groupCol}, PreserveQuoted: t.PreserveQuoted}
+	idx := make([]int, len(aggs))
+	for k, spec := range aggs {
+		if idx[k], err = t.index(spec.Column); err != nil {
+			return Table{}, err
+		}
+		if spec.Func < AggCount || spec.Func > AggMean {
+			return Table{}, fmt.Errorf("csv: invalid %v for column %q", spec.Func, spec.Column)
+		}
+		name := spec.Name
+		if name == "" {
+			name = spec.Func.String() + "(" + spec.Column + ")"
+		}
+		out.Headers = append(out.Headers, name)
+	}
+
+	// aggState holds the running aggregate of one column of a group.
+	type aggState struct {
+		count         int
+		sum, min, max float64
+	}
+	rowOf := make(map[string]int)
+	var states [][]aggState
+	field := func(record []Column, i int) Column {
+		if i < len(record) {
+			return record[i]
+		}
+		return Column{}
+	}
+	for _, record := range t.Rows {
+		key := field(record, g)
+		r, ok := rowOf[key.Value]
+		if !ok {
+			r = len(out.Rows)
+			rowOf[key.Value] = r
+			out.Rows = append(out.Rows, append(make([]Column, 0, 1+len(aggs)), key))
+			states = append(states, make([]aggState, len(aggs)))
+		}
+		for k, spec := range aggs {
+			col := field(record, idx[k])
+			if col.IsMissing() {
+				continue
+			}
+			st := &states[r][k]
+			if spec.Func != AggCount {
+				v, err := col.AsFloat64()
+				if err != nil {
+					return Table{}, fmt.Errorf("csv: %v of column %q for %q: %w", spec.Func, spec.Column, key.Value, err)
+				}
+				if st.count == 0 || v < st.min {
+					st.min = v
+				}
+				if st.count == 0 || v > st.max {
+					st.max = v
+				}
+				st.sum += v
+			}
+			st.count++
+		}
+	}
+
+	format := func(v float64) Column {
+		return Column{Value: strconv.FormatFloat(v, 'g', -1, 64)}
+	}
+	for r := range out.Rows {
+		for k, spec := range aggs {
+			st := states[r][k]
+			var col Column
+			switch {
+			case spec.Func == AggCount:
+				col = Column{Value: strconv.Itoa(st.count)}
+			case st.count == 0:
+			case spec.Func == AggSum:
+				col = format(st.sum)
+			case spec.Func == AggMin:
+				col = format(st.min)
+			case spec.Func == AggMax:
+				col = format(st.max)
+			case spec.Func == AggMean:
+				col = format(st.sum / float64(st.count))
+			}
+			out.Rows[r] = append(out.Rows[r], col)
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,83 @@
+package csv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func newSalesTable() *Table {
+	return NewTable([]string{"region", "item", "price"}, [][]Column{
+		{c("north"), c("pen"), c("1.5")},
+		{c("south"), c("ink"), c("3")},
+		{c("north"), c("pad"), c("4")},
+		{c(""), c("box"), c("2")},
+		{c("north"), c("cap"), c(" ")},
+		{c("east"), c("lid")},
+	})
+}
+
+func TestTableGroupBy(t *testing.T) {
+	tbl := newSalesTable()
+	groups, err := tbl.GroupBy("region")
+	if err != nil {
+		t.Fatalf("GroupBy() error: %v", err)
+	}
+	want := map[string]Table{
+		"north": {Headers: tbl.Headers, Rows: [][]Column{tbl.Rows[0], tbl.Rows[2], tbl.Rows[4]}},
+		"south": {Headers: tbl.Headers, Rows: [][]Column{tbl.Rows[1]}},
+		"":      {Headers: tbl.Headers, Rows: [][]Column{tbl.Rows[3]}},
+		"east":  {Headers: tbl.Headers, Rows: [][]Column{tbl.Rows[5]}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("GroupBy() =\n%v\nwant\n%v", groups, want)
+	}
+
+	if _, err := tbl.GroupBy("city"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("GroupBy() by unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	empty := NewTable(tbl.Headers, nil)
+	if groups, err := empty.GroupBy("region"); err != nil || len(groups) != 0 {
+		t.Errorf("GroupBy() on empty table = %v, %v", groups, err)
+	}
+}
+
+func TestTableAggregate(t *testing.T) {
+	tbl := newSalesTable()
+	out, err := tbl.Aggregate("region", []AggSpec{
+		{Column: "item", Func: AggCount},
+		{Column: "price", Func: AggSum},
+		{Column: "price", Func: AggMin, Name: "cheapest"},
+		{Column: "price", Func: AggMax},
+		{Column: "price", Func: AggMean},
+		{Column: "price", Func: AggCount},
+	})
+	if err != nil {
+		t.Fatalf("Aggregate() error: %v", err)
+	}
+	want := Table{
+		Headers: []string{"region", "count(item)", "sum(price)", "cheapest", "max(price)", "mean(price)", "count(price)"},
+		Rows: [][]Column{
+			{c("north"), c("3"), c("5.5"), c("1.5"), c("4"), c("2.75"), c("2")},
+			{c("south"), c("1"), c("3"), c("3"), c("3"), c("3"), c("1")},
+			{c(""), c("1"), c("2"), c("2"), c("2"), c("2"), c("1")},
+			{c("east"), c("1"), {}, {}, {}, {}, c("0")},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Aggregate() =\n%v\nwant\n%v", out, want)
+	}
+
+	_, err = tbl.Aggregate("region", []AggSpec{{Column: "item", Func: AggSum}})
+	if !errors.Is(err, strconv.ErrSyntax) {
+		t.Errorf("Aggregate() of non-numeric values error = %v, want %v", err, strconv.ErrSyntax)
+	}
+	_, err = tbl.Aggregate("region", []AggSpec{{Column: "qty", Func: AggSum}})
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Aggregate() of unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if _, err = tbl.Aggregate("region", []AggSpec{{Column: "price", Func: AggMean + 1}}); err == nil {
+		t.Error("Aggregate() with invalid function: expected error")
+	}
+}
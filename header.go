@@ -0,0 +1,129 @@
+package csv
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A DuplicateColumnError is returned by NewHeaderReader and Table.ReadFrom
+// if a header row holds the same column name more than once.
+type DuplicateColumnError struct {
+	Name        string // The repeated column name
+	FirstIndex  int    // Index of the first column called Name
+	SecondIndex int    // Index of the second column called Name
+
+	// Indexes holds the indexes of all columns called Name,
+	// starting with FirstIndex and SecondIndex.
+	Indexes []int
+}
+
+func (e *DuplicateColumnError) Error() string {
+	idx := make([]string, len(e.Indexes))
+	for i, n := range e.Indexes {
+		idx[i] = strconv.Itoa(n)
+	}
+	return fmt.Sprintf("csv: duplicate column %q at indexes %s", e.Name, strings.Join(idx, ", "))
+}
+
+// Unwrap returns ErrDuplicateColumn.
+func (e *DuplicateColumnError) Unwrap() error { return ErrDuplicateColumn }
+
+// uniqueHeaders returns a *DuplicateColumnError for the first column name
+// in headers that appears more than once. If rename is true, it instead
+// renames the second and later columns with a repeated name by appending
+// "_2", "_3", and so on, skipping names that are taken.
+func uniqueHeaders(headers []string, rename bool) error {
+	first := make(map[string]int, len(headers))
+	for i, h := range headers {
+		if j, ok := first[h]; ok && !rename {
+			e := &DuplicateColumnError{Name: h, FirstIndex: j, SecondIndex: i}
+			for k := j; k < len(headers); k++ {
+				if headers[k] == h {
+					e.Indexes = append(e.Indexes, k)
+				}
+			}
+			return e
+		} else if !ok {
+			first[h] = i
+		}
+	}
+	if !rename || len(first) == len(headers) {
+		return nil
+	}
+	used := make(map[string]bool, len(headers))
+	for i, h := range headers {
+		if !used[h] {
+			used[h] = true
+			continue
+		}
+		for n := 2; ; n++ {
+			name := h + "_" + strconv.Itoa(n)
+			if _, taken := first[name]; !taken && !used[name] {
+				headers[i] = name
+				used[name] = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// A HeaderReader reads records from a CSV file whose first record is a
+// header row holding the names of the columns.
+type HeaderReader struct {
+	r       *Reader
+	headers []string
+}
+
+// NewHeaderReader returns a HeaderReader reading from r.
+// It reads the first record of r, skipping comments and blank lines as
+// usual, and uses its fields as column names. If r.FieldsPerRecord is 0,
+// subsequent records must have as many fields as the header row.
+// ParseErrors returned by r from then on carry the name of the field
+// in error in FieldName.
+// If a column name appears more than once, NewHeaderReader returns a
+// *DuplicateColumnError, unless r.AllowDuplicateHeaders is set.
+// If the input is empty, NewHeaderReader returns io.EOF.
+func NewHeaderReader(r *Reader) (*HeaderReader, error) {
+	record, err := r.Read()
+	if err != nil {
+		return nil, err
+	}
+	headers := make([]string, len(record))
+	for i, col := range record {
+		headers[i] = col.Value
+	}
+	if err := uniqueHeaders(headers, r.AllowDuplicateHeaders); err != nil {
+		return nil, err
+	}
+	r.fieldNames = headers
+	return &HeaderReader{r: r, headers: headers}, nil
+}
+
+// Headers returns the column names read from the header row.
+func (h *HeaderReader) Headers() []string {
+	return h.headers
+}
+
+// ReadMap reads one record and returns it as a map from column name to
+// field. Fields beyond the last header column are stored under the names
+// "_extra_0", "_extra_1", and so on. If the record has fewer fields than
+// the header, the missing columns are absent from the map.
+// Like Read, ReadMap returns the record along with ErrFieldCount if the
+// record has an unexpected number of fields.
+func (h *HeaderReader) ReadMap() (map[string]Column, error) {
+	record, err := h.r.Read()
+	if record == nil {
+		return nil, err
+	}
+	m := make(map[string]Column, len(record))
+	for i, col := range record {
+		if i < len(h.headers) {
+			m[h.headers[i]] = col
+		} else {
+			m["_extra_"+strconv.Itoa(i-len(h.headers))] = col
+		}
+	}
+	return m, err
+}
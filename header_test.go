@@ -0,0 +1,176 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestHeaderReader(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Input   string
+		Headers []string
+		Output  []map[string]Column
+		Error   error
+
+		// These fields are copied into the Reader
+		Comment            rune
+		UseFieldsPerRecord bool // false (default) means FieldsPerRecord is -1
+		FieldsPerRecord    int
+		AllowDuplicates    bool
+	}{{
+		Name:    "Simple",
+		Input:   "name,lang\nRob,go\n\"Ken\",C\n",
+		Headers: []string{"name", "lang"},
+		Output: []map[string]Column{
+			{"name": c("Rob"), "lang": c("go")},
+			{"name": q("Ken"), "lang": c("C")},
+		},
+	}, {
+		Name:    "HeaderOnly",
+		Input:   "name,lang\n",
+		Headers: []string{"name", "lang"},
+	}, {
+		Name:    "SkipCommentsAndBlankLines",
+		Input:   "# exported 2021-05-01\n\nname,lang\n\n# data\nRob,go\n",
+		Headers: []string{"name", "lang"},
+		Output:  []map[string]Column{{"name": c("Rob"), "lang": c("go")}},
+		Comment: '#',
+	}, {
+		Name:    "ExtraColumns",
+		Input:   "a,b\n1,2,3,4\n5\n",
+		Headers: []string{"a", "b"},
+		Output: []map[string]Column{
+			{"a": c("1"), "b": c("2"), "_extra_0": c("3"), "_extra_1": c("4")},
+			{"a": c("5")},
+		},
+	}, {
+		Name:               "InferFieldsPerRecord",
+		Input:              "a,b\n1,2\n3,4,5\n",
+		Headers:            []string{"a", "b"},
+		Output:             []map[string]Column{{"a": c("1"), "b": c("2")}},
+		Error:              &ParseError{StartLine: 3, Line: 3, Err: ErrFieldCount},
+		UseFieldsPerRecord: true,
+	}, {
+		Name:    "FieldName",
+		Input:   "id,email\n1,a@b\n2,x\"y\n",
+		Headers: []string{"id", "email"},
+		Output:  []map[string]Column{{"id": c("1"), "email": c("a@b")}},
+		Error:   &ParseError{StartLine: 3, Line: 3, Column: 3, FieldName: "email", Err: ErrBareQuote},
+	}, {
+		Name:    "FieldNameMultiLine",
+		Input:   "id,email\n\"1\n\"x,y\n",
+		Headers: []string{"id", "email"},
+		Error:   &ParseError{StartLine: 2, Line: 3, Column: 0, FieldName: "id", Err: ErrQuote},
+	}, {
+		Name:    "FieldNameExtra",
+		Input:   "id\n1,x\"\n",
+		Headers: []string{"id"},
+		Error:   &ParseError{StartLine: 2, Line: 2, Column: 3, Err: ErrBareQuote},
+	}, {
+		Name:  "DuplicateHeader",
+		Input: "id,name,email,name\n1,a,b,c\n",
+		Error: &DuplicateColumnError{Name: "name", FirstIndex: 1, SecondIndex: 3, Indexes: []int{1, 3}},
+	}, {
+		Name:  "AllSameHeaders",
+		Input: "x,x,x\n",
+		Error: &DuplicateColumnError{Name: "x", FirstIndex: 0, SecondIndex: 1, Indexes: []int{0, 1, 2}},
+	}, {
+		Name:            "AllowDuplicateHeaders",
+		Input:           "x,x,y,x,x_3\n1,2,3,4,5\n",
+		Headers:         []string{"x", "x_2", "y", "x_4", "x_3"},
+		Output:          []map[string]Column{{"x": c("1"), "x_2": c("2"), "y": c("3"), "x_4": c("4"), "x_3": c("5")}},
+		AllowDuplicates: true,
+	}, {
+		Name:  "Empty",
+		Input: "",
+		Error: io.EOF,
+	}, {
+		Name:  "BadHeader",
+		Input: "a,\"b\n",
+		Error: &ParseError{StartLine: 1, Line: 2, Column: 0, Err: ErrQuote},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			r.Comment = tt.Comment
+			r.AllowDuplicateHeaders = tt.AllowDuplicates
+			if tt.UseFieldsPerRecord {
+				r.FieldsPerRecord = tt.FieldsPerRecord
+			} else {
+				r.FieldsPerRecord = -1
+			}
+
+			var out []map[string]Column
+			h, err := NewHeaderReader(r)
+			if err == nil {
+				if !reflect.DeepEqual(h.Headers(), tt.Headers) {
+					t.Errorf("Headers() = %q, want %q", h.Headers(), tt.Headers)
+				}
+				for {
+					var m map[string]Column
+					m, err = h.ReadMap()
+					if err != nil {
+						break
+					}
+					out = append(out, m)
+				}
+				if err == io.EOF && tt.Error != io.EOF {
+					err = nil
+				}
+			}
+			if !reflect.DeepEqual(err, tt.Error) {
+				t.Errorf("error:\ngot  %v\nwant %v", err, tt.Error)
+			} else if !reflect.DeepEqual(out, tt.Output) {
+				t.Errorf("output:\ngot  %v\nwant %v", out, tt.Output)
+			}
+		})
+	}
+}
+
+func TestParseErrorFieldName(t *testing.T) {
+	err := &ParseError{StartLine: 5, Line: 5, Column: 3, FieldName: "email", Err: ErrBareQuote}
+	const want = `parse error on line 5, column 3: field "email": bare " in non-quoted-field`
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+	err = &ParseError{StartLine: 4, Line: 5, Column: 0, FieldName: "note", Err: ErrQuote}
+	const wantMulti = `record on line 4; parse error on line 5, column 0: field "note": extraneous or missing " in quoted-field`
+	if err.Error() != wantMulti {
+		t.Errorf("Error() = %q, want %q", err.Error(), wantMulti)
+	}
+	if !errors.Is(err, &ParseError{FieldName: "note"}) || errors.Is(err, &ParseError{FieldName: "id"}) {
+		t.Errorf("errors.Is() does not match on FieldName")
+	}
+
+	// Field names are also taken from a schema.
+	r := NewReader(strings.NewReader("1,\"2\"x\n"))
+	r.SetSchema(&Schema{Columns: []ColumnSchema{{Name: "a"}, {Name: "b"}}})
+	_, err2 := r.Read()
+	if !errors.Is(err2, &ParseError{FieldName: "b", Err: ErrQuote}) {
+		t.Errorf("Read() error = %v, want ErrQuote in field b", err2)
+	}
+}
+
+func TestDuplicateColumnError(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b,a,b,a\n"))
+	_, err := NewHeaderReader(r)
+	if !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("NewHeaderReader() error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	if want := `csv: duplicate column "a" at indexes 0, 2, 4`; err == nil || err.Error() != want {
+		t.Errorf("Error() = %v, want %q", err, want)
+	}
+
+	var tbl Table
+	if _, err := tbl.ReadFrom(strings.NewReader("a,b,b\n1,2,3\n")); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("Table.ReadFrom() error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	if tbl.Headers != nil {
+		t.Errorf("Table.ReadFrom() changed the table to %v", tbl)
+	}
+}
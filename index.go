@@ -0,0 +1,63 @@
+package csv
+
+// BuildIndex indexes the rows of t by the value of the column called col,
+// so that IndexOf finds them without scanning t. Fields are told apart by
+// their Value only, and missing fields read as empty values. The index
+// is kept up to date by the methods of Table that change rows, but not
+// when Rows is modified directly; call InvalidateIndex or
+// InvalidateAllIndexes afterwards, or BuildIndex again.
+func (t *Table) BuildIndex(col string) error {
+	i, err := t.index(col)
+	if err != nil {
+		return err
+	}
+	index := make(map[string][]int)
+	for r, record := range t.Rows {
+		var value string
+		if i < len(record) {
+			value = record[i].Value
+		}
+		index[value] = append(index[value], r)
+	}
+	if t.indices == nil {
+		t.indices = make(map[string]map[string][]int)
+	}
+	t.indices[col] = index
+	return nil
+}
+
+// IndexOf returns the indexes of the rows whose field in column col has
+// the given value, in ascending order, or nil if there are none. It uses
+// the index built by BuildIndex if there is one and scans the rows
+// otherwise. The returned slice is newly allocated.
+func (t *Table) IndexOf(col, value string) ([]int, error) {
+	i, err := t.index(col)
+	if err != nil {
+		return nil, err
+	}
+	if index, ok := t.indices[col]; ok {
+		return append([]int(nil), index[value]...), nil
+	}
+	var rows []int
+	for r, record := range t.Rows {
+		var v string
+		if i < len(record) {
+			v = record[i].Value
+		}
+		if v == value {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+// InvalidateIndex discards the index built by BuildIndex for the column
+// called col, if any.
+func (t *Table) InvalidateIndex(col string) {
+	delete(t.indices, col)
+}
+
+// InvalidateAllIndexes discards all indexes built by BuildIndex.
+func (t *Table) InvalidateAllIndexes() {
+	t.indices = nil
+}
@@ -0,0 +1,94 @@
+package csv
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTableIndexOf(t *testing.T) {
+	tbl := NewTable([]string{"name", "city"}, [][]Column{
+		{c("Ann"), c("Berlin")},
+		{c("Bob"), c("")},
+		{c("Cid"), c("Berlin")},
+		{c("Dan")},
+		{c("Eve"), c("Paris")},
+	})
+	lookups := []struct {
+		Value string
+		Want  []int
+	}{
+		{Value: "Berlin", Want: []int{0, 2}},
+		{Value: "Paris", Want: []int{4}},
+		{Value: "", Want: []int{1, 3}},
+		{Value: "Rome", Want: nil},
+	}
+	check := func(name string) {
+		t.Helper()
+		for _, l := range lookups {
+			got, err := tbl.IndexOf("city", l.Value)
+			if err != nil {
+				t.Fatalf("%s: IndexOf(%q) error: %v", name, l.Value, err)
+			}
+			if !reflect.DeepEqual(got, l.Want) {
+				t.Errorf("%s: IndexOf(%q) = %v, want %v", name, l.Value, got, l.Want)
+			}
+		}
+	}
+
+	check("without index")
+	if err := tbl.BuildIndex("city"); err != nil {
+		t.Fatalf("BuildIndex() error: %v", err)
+	}
+	check("with index")
+
+	// The index is only used if it exists.
+	tbl.indices["city"]["Rome"] = []int{0}
+	if got, _ := tbl.IndexOf("city", "Rome"); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("IndexOf() did not use the index")
+	}
+	tbl.InvalidateIndex("city")
+	check("after InvalidateIndex")
+
+	// Changing rows through Table methods invalidates the index.
+	if err := tbl.BuildIndex("city"); err != nil {
+		t.Fatalf("BuildIndex() error: %v", err)
+	}
+	if err := tbl.AddRow("Fay", "Paris"); err != nil {
+		t.Fatalf("AddRow() error: %v", err)
+	}
+	if got, _ := tbl.IndexOf("city", "Paris"); !reflect.DeepEqual(got, []int{4, 5}) {
+		t.Errorf("IndexOf() after AddRow = %v, want [4 5]", got)
+	}
+	if err := tbl.BuildIndex("city"); err != nil {
+		t.Fatalf("BuildIndex() error: %v", err)
+	}
+	if err := tbl.Set(0, "city", c("Rome")); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if got, _ := tbl.IndexOf("city", "Rome"); !reflect.DeepEqual(got, []int{0}) {
+		t.Errorf("IndexOf() after Set = %v, want [0]", got)
+	}
+
+	// Renaming a column keeps its index.
+	if err := tbl.BuildIndex("city"); err != nil {
+		t.Fatalf("BuildIndex() error: %v", err)
+	}
+	if err := tbl.RenameColumn("city", "town"); err != nil {
+		t.Fatalf("RenameColumn() error: %v", err)
+	}
+	if _, ok := tbl.indices["town"]; !ok {
+		t.Errorf("RenameColumn() dropped the index")
+	}
+	tbl.InvalidateAllIndexes()
+	if tbl.indices != nil {
+		t.Errorf("InvalidateAllIndexes() left %v", tbl.indices)
+	}
+
+	if err := tbl.BuildIndex("country"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("BuildIndex() of unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if _, err := tbl.IndexOf("country", "x"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("IndexOf() of unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+}
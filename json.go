@@ -0,0 +1,215 @@
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// jsonColumn is the JSON form of a field written by ToJSON if
+// PreserveQuoted is set.
+type jsonColumn struct {
+	Value  string `json:"value"`
+	Quoted bool   `json:"quoted"`
+}
+
+var errJSONColumn = errors.New("csv: cannot unmarshal JSON array into Column")
+
+// quotedJSONColumn is the JSON form of a quoted Column written by
+// MarshalJSON when built with the csv_preserve_quoted tag.
+type quotedJSONColumn struct {
+	V string `json:"v"`
+	Q bool   `json:"q"`
+}
+
+// MarshalJSON encodes c as a JSON string holding its value, or as null if
+// c is NULL. If the package is built with the csv_preserve_quoted build tag,
+// a quoted column is encoded as an object such as {"v":"x","q":true}.
+// It implements json.Marshaler.
+func (c Column) MarshalJSON() ([]byte, error) {
+	switch {
+	case c.IsNull:
+		return []byte("null"), nil
+	case c.Quoted && preserveQuotedJSON:
+		return json.Marshal(quotedJSONColumn{c.Value, true})
+	}
+	return json.Marshal(c.Value)
+}
+
+// UnmarshalJSON decodes a Column encoded by MarshalJSON in either form.
+// null decodes to a NULL column, and numbers and booleans to an unquoted
+// column holding them as written.
+// It implements json.Unmarshaler.
+func (c *Column) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if len(b) == 0 {
+		return errJSONColumn
+	}
+	switch b[0] {
+	case 'n':
+		*c = Column{IsNull: true}
+		return nil
+	case '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		*c = Column{Value: s}
+		return nil
+	case '{':
+		var qc quotedJSONColumn
+		if err := json.Unmarshal(b, &qc); err != nil {
+			return err
+		}
+		*c = Column{Value: qc.V, Quoted: qc.Q}
+		return nil
+	case '[':
+		return errJSONColumn
+	}
+	*c = Column{Value: string(b)}
+	return nil
+}
+
+// ToJSON writes the table to w as a JSON array holding one object per row,
+// which maps the column names to the field values in the order of Headers.
+// NULL fields are written as null, and missing fields as empty strings.
+// If PreserveQuoted is true, each value is written as an object such as
+// {"value":"x","quoted":true} instead of a plain string.
+func (t *Table) ToJSON(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	keys := make([][]byte, len(t.Headers))
+	for i, h := range t.Headers {
+		key, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		keys[i] = key
+	}
+	bw.WriteByte('[')
+	for r, record := range t.Rows {
+		if r > 0 {
+			bw.WriteByte(',')
+		}
+		bw.WriteByte('{')
+		for i, key := range keys {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(key)
+			bw.WriteByte(':')
+			var col Column
+			if i < len(record) {
+				col = record[i]
+			}
+			var v interface{} = col.Value
+			switch {
+			case col.IsNull:
+				v = nil
+			case t.PreserveQuoted:
+				v = jsonColumn{col.Value, col.Quoted}
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+			bw.Write(value)
+		}
+		bw.WriteByte('}')
+	}
+	bw.WriteString("]\n")
+	return bw.Flush()
+}
+
+// FromJSON replaces the contents of the table with a JSON array of objects
+// read from r, as written by ToJSON. The headers are the keys of the first
+// object in order, followed by the keys first seen in later objects.
+// Values may be strings, null for NULL fields, objects as written by
+// ToJSON with PreserveQuoted set, or numbers and booleans, which are kept
+// as written. If an error occurs, the table is left unchanged.
+func (t *Table) FromJSON(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	var headers []string
+	index := make(map[string]int)
+	var rows [][]Column
+	for dec.More() {
+		if err := expectDelim(dec, '{'); err != nil {
+			return err
+		}
+		row := make([]Column, len(headers))
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key := tok.(string) // Object keys are always strings
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return err
+			}
+			col, err := jsonToColumn(raw)
+			if err != nil {
+				return fmt.Errorf("csv: key %q: %w", key, err)
+			}
+			i, ok := index[key]
+			if !ok {
+				i = len(headers)
+				index[key] = i
+				headers = append(headers, key)
+			}
+			for len(row) <= i {
+				row = append(row, Column{})
+			}
+			row[i] = col
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	if err := expectDelim(dec, ']'); err != nil {
+		return err
+	}
+	t.Headers, t.Rows = headers, rows
+	t.InvalidateAllIndexes()
+	return nil
+}
+
+// expectDelim reads the next token from dec and checks that it is delim.
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("csv: %w: unexpected %v", ErrJSONShape, tok)
+	}
+	return nil
+}
+
+// jsonToColumn converts a JSON value read by FromJSON to a Column.
+func jsonToColumn(raw json.RawMessage) (Column, error) {
+	switch raw[0] {
+	case 'n':
+		return Column{IsNull: true}, nil
+	case '"':
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return Column{Value: s}, err
+	case '{':
+		var jc jsonColumn
+		err := json.Unmarshal(raw, &jc)
+		return Column{Value: jc.Value, Quoted: jc.Quoted}, err
+	case '[':
+		return Column{}, ErrJSONShape
+	}
+	return Column{Value: string(bytes.TrimSpace(raw))}, nil
+}
@@ -0,0 +1,125 @@
+package csv
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTableToJSON(t *testing.T) {
+	tbl := NewTable([]string{"name", `a "key"`, "<tag>"}, [][]Column{
+		{c("Rob"), q("x,y"), {IsNull: true}},
+		{q("Ken"), c("line\nbreak")},
+	})
+	var b strings.Builder
+	if err := tbl.ToJSON(&b); err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+	want := `[{"name":"Rob","a \"key\"":"x,y","\u003ctag\u003e":null},` +
+		`{"name":"Ken","a \"key\"":"line\nbreak","\u003ctag\u003e":""}]` + "\n"
+	if got := b.String(); got != want {
+		t.Errorf("ToJSON():\ngot  %s\nwant %s", got, want)
+	}
+
+	b.Reset()
+	tbl.PreserveQuoted = true
+	if err := tbl.ToJSON(&b); err != nil {
+		t.Fatalf("ToJSON() error: %v", err)
+	}
+	var back Table
+	if err := back.FromJSON(strings.NewReader(b.String())); err != nil {
+		t.Fatalf("FromJSON() error: %v", err)
+	}
+	wantRows := [][]Column{
+		{c("Rob"), q("x,y"), {IsNull: true}},
+		{q("Ken"), c("line\nbreak"), c("")},
+	}
+	if !reflect.DeepEqual(back.Headers, tbl.Headers) || !reflect.DeepEqual(back.Rows, wantRows) {
+		t.Errorf("FromJSON(ToJSON()) = %q %v, want %q %v", back.Headers, back.Rows, tbl.Headers, wantRows)
+	}
+
+	var empty Table
+	b.Reset()
+	if err := empty.ToJSON(&b); err != nil || b.String() != "[]\n" {
+		t.Errorf("ToJSON() of empty table = %q, %v, want []", b.String(), err)
+	}
+}
+
+func TestTableFromJSON(t *testing.T) {
+	input := `[
+		{"b": "1", "a": 2.50},
+		{"c": true, "a": null},
+		{}
+	]`
+	var tbl Table
+	if err := tbl.FromJSON(strings.NewReader(input)); err != nil {
+		t.Fatalf("FromJSON() error: %v", err)
+	}
+	if want := []string{"b", "a", "c"}; !reflect.DeepEqual(tbl.Headers, want) {
+		t.Errorf("FromJSON() headers = %q, want %q", tbl.Headers, want)
+	}
+	want := [][]Column{
+		{c("1"), c("2.50")},
+		{{}, {IsNull: true}, c("true")},
+		{{}, {}, {}},
+	}
+	if !reflect.DeepEqual(tbl.Rows, want) {
+		t.Errorf("FromJSON() rows = %v, want %v", tbl.Rows, want)
+	}
+
+	for _, input := range []string{`{"a":"b"}`, `["a"]`, `[{"a":["b"]}]`, `[{"a":"b"}`, ``} {
+		tbl := Table{Headers: []string{"x"}}
+		if err := tbl.FromJSON(strings.NewReader(input)); err == nil {
+			t.Errorf("FromJSON(%q) succeeded, want error", input)
+		}
+		if len(tbl.Headers) != 1 {
+			t.Errorf("FromJSON(%q) modified the table on error", input)
+		}
+	}
+	if err := tbl.FromJSON(strings.NewReader(`["a"]`)); !errors.Is(err, ErrJSONShape) {
+		t.Errorf("FromJSON() error = %v, want %v", err, ErrJSONShape)
+	}
+}
+
+func TestColumnMarshalJSON(t *testing.T) {
+	record := []Column{c("a"), q(`b "c"`), {IsNull: true}, c("")}
+	got, err := json.Marshal(record)
+	if err != nil {
+		t.Fatalf("Marshal() error: %v", err)
+	}
+	want := `["a","b \"c\"",null,""]`
+	if preserveQuotedJSON {
+		want = `["a",{"v":"b \"c\"","q":true},null,""]`
+	}
+	if string(got) != want {
+		t.Errorf("Marshal() = %s, want %s", got, want)
+	}
+	var back []Column
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	if !preserveQuotedJSON {
+		record[1].Quoted = false
+	}
+	if !reflect.DeepEqual(back, record) {
+		t.Errorf("Unmarshal(Marshal()) = %v, want %v", back, record)
+	}
+}
+
+func TestColumnUnmarshalJSON(t *testing.T) {
+	var got []Column
+	input := `["x", {"v": "y", "q": true}, {"v": "z"}, null, 12.50, -1e3, true]`
+	if err := json.Unmarshal([]byte(input), &got); err != nil {
+		t.Fatalf("Unmarshal() error: %v", err)
+	}
+	want := []Column{c("x"), q("y"), c("z"), {IsNull: true}, c("12.50"), c("-1e3"), c("true")}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() = %v, want %v", got, want)
+	}
+	var col Column
+	if err := json.Unmarshal([]byte(`["x"]`), &col); err == nil {
+		t.Errorf("Unmarshal() of array succeeded, want error")
+	}
+}
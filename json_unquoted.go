@@ -0,0 +1,7 @@
+//go:build !csv_preserve_quoted
+// +build !csv_preserve_quoted
+
+package csv
+
+// preserveQuotedJSON makes Column.MarshalJSON keep the Quoted flag.
+const preserveQuotedJSON = false
@@ -0,0 +1,281 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// structField describes a struct field mapped to a CSV column.
+type structField struct {
+	name      string
+	index     []int // Index sequence for reflect.Value.FieldByIndex
+	omitEmpty bool
+}
+
+var (
+	columnType = reflect.TypeOf(Column{})
+	timeType   = reflect.TypeOf(time.Time{})
+)
+
+// structFields returns the fields of struct type t that map to columns, in
+// declaration order. Fields of embedded structs are included in place of
+// the embedded field.
+func structFields(t reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup("csv")
+		if tag == "-" {
+			continue
+		}
+		idx := append(append([]int(nil), index...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !hasTag && ft.Kind() == reflect.Struct && ft != timeType {
+			fields = append(fields, structFields(ft, idx)...)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue // Unexported
+		}
+		name, opts := tag, ""
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			index:     idx,
+			omitEmpty: opts == "omitempty",
+		})
+	}
+	return fields
+}
+
+// structValue returns the struct v points to, or an error naming fn.
+func structValue(v interface{}, fn string) (reflect.Value, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("csv: %s of non-struct type %T", fn, v)
+	}
+	return rv, nil
+}
+
+// MarshalRecord returns the fields of the struct v, or the struct v points
+// to, as a record. Fields are converted in declaration order, with the
+// fields of embedded structs flattened in place. The struct tag
+// `csv:"name,omitempty"` sets the column name of a field, and omitempty
+// writes an empty column for a zero value. Fields tagged `csv:"-"` and
+// unexported fields are skipped.
+//
+// Supported field types are string, the integer, float and bool kinds,
+// time.Time (formatted as RFC 3339), Column, and pointers to these.
+// Nil pointers produce empty columns.
+func MarshalRecord(v interface{}) ([]Column, error) {
+	rv, err := structValue(v, "MarshalRecord")
+	if err != nil {
+		return nil, err
+	}
+	fields := structFields(rv.Type(), nil)
+	record := make([]Column, len(fields))
+	for i, f := range fields {
+		fv, ok := fieldByIndex(rv, f.index, false)
+		if !ok || f.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if record[i], err = marshalValue(fv); err != nil {
+			return nil, fmt.Errorf("csv: field %s: %w", f.name, err)
+		}
+	}
+	return record, nil
+}
+
+// MarshalHeader returns the column names MarshalRecord uses for the
+// struct v, or the struct v points to.
+func MarshalHeader(v interface{}) ([]string, error) {
+	rv, err := structValue(v, "MarshalHeader")
+	if err != nil {
+		return nil, err
+	}
+	fields := structFields(rv.Type(), nil)
+	header := make([]string, len(fields))
+	for i, f := range fields {
+		header[i] = f.name
+	}
+	return header, nil
+}
+
+// UnmarshalRecord stores the fields of record in the struct pointed to by
+// v, matching columns to struct fields by position, in the order
+// MarshalRecord uses. Columns beyond the last struct field are ignored,
+// and struct fields without a column are left unchanged.
+//
+// An empty column sets a pointer field to nil and any other field to its
+// zero value.
+func UnmarshalRecord(record []Column, v interface{}) error {
+	return unmarshalRecord(nil, record, v)
+}
+
+// UnmarshalRecordHeader is like UnmarshalRecord, but matches columns to
+// struct fields by name. The name of the i'th column of record is
+// header[i].
+func UnmarshalRecordHeader(header []string, record []Column, v interface{}) error {
+	if header == nil {
+		header = []string{}
+	}
+	return unmarshalRecord(header, record, v)
+}
+
+func unmarshalRecord(header []string, record []Column, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("csv: UnmarshalRecord requires a non-nil pointer to a struct, not %T", v)
+	}
+	rv = rv.Elem()
+	for i, f := range structFields(rv.Type(), nil) {
+		col := i
+		if header != nil {
+			col = -1
+			for j, h := range header {
+				if h == f.name {
+					col = j
+					break
+				}
+			}
+		}
+		if col < 0 || col >= len(record) {
+			continue
+		}
+		fv, _ := fieldByIndex(rv, f.index, true)
+		if err := unmarshalValue(record[col], fv); err != nil {
+			return fmt.Errorf("csv: field %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex is like reflect.Value.FieldByIndex, but reports false
+// instead of panicking when it meets a nil embedded pointer. If alloc is
+// true, nil embedded pointers are set to new values instead.
+func fieldByIndex(v reflect.Value, index []int, alloc bool) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !alloc {
+					return reflect.Value{}, false
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+var errUnsupportedType = errors.New("unsupported type")
+
+func marshalValue(v reflect.Value) (Column, error) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return Column{}, nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case columnType:
+		return v.Interface().(Column), nil
+	case timeType:
+		return Column{Value: v.Interface().(time.Time).Format(time.RFC3339Nano)}, nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return Column{Value: v.String()}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Column{Value: strconv.FormatInt(v.Int(), 10)}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Column{Value: strconv.FormatUint(v.Uint(), 10)}, nil
+	case reflect.Float32, reflect.Float64:
+		return Column{Value: strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())}, nil
+	case reflect.Bool:
+		return Column{Value: strconv.FormatBool(v.Bool())}, nil
+	}
+	return Column{}, fmt.Errorf("%w %s", errUnsupportedType, v.Type())
+}
+
+func unmarshalValue(col Column, v reflect.Value) error {
+	if v.Kind() == reflect.Ptr {
+		if col.Value == "" && v.Type().Elem() != columnType {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		v = v.Elem()
+	}
+	if v.Type() == columnType {
+		v.Set(reflect.ValueOf(col))
+		return nil
+	}
+	if col.Value == "" {
+		v.Set(reflect.Zero(v.Type()))
+		return nil
+	}
+	if v.Type() == timeType {
+		t, err := col.AsTime(time.RFC3339)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(col.Value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := col.AsInt64()
+		if err != nil {
+			return err
+		}
+		if v.OverflowInt(i) {
+			return conversionError(col.Value, v.Type().String(), strconv.ErrRange)
+		}
+		v.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(col.Value, 10, 64)
+		if err != nil || v.OverflowUint(u) {
+			if err == nil {
+				err = strconv.ErrRange
+			}
+			return conversionError(col.Value, v.Type().String(), err)
+		}
+		v.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(col.Value, v.Type().Bits())
+		if err != nil {
+			return conversionError(col.Value, v.Type().String(), err)
+		}
+		v.SetFloat(f)
+	case reflect.Bool:
+		b, err := col.AsBool()
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedType, v.Type())
+	}
+	return nil
+}
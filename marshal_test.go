@@ -0,0 +1,182 @@
+package csv
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type Audit struct {
+	Created time.Time `csv:"created"`
+	Note    *string   `csv:"note,omitempty"`
+}
+
+type person struct {
+	Name    string  `csv:"name"`
+	Age     int     `csv:"age"`
+	Height  float64 `csv:"height,omitempty"`
+	Admin   bool    `csv:"admin"`
+	Score   *int8   `csv:"score"`
+	Raw     Column  `csv:"raw"`
+	Ignored string  `csv:"-"`
+	secret  string
+	Audit
+	Count uint16
+}
+
+func TestMarshalRecord(t *testing.T) {
+	score := int8(-3)
+	note := "hi"
+	p := person{
+		Name:    "Rob, Pike",
+		Age:     64,
+		Admin:   true,
+		Score:   &score,
+		Raw:     q("x"),
+		Ignored: "ignored",
+		secret:  "secret",
+		Audit:   Audit{Created: time.Date(2021, 5, 1, 12, 0, 0, 0, time.UTC), Note: &note},
+		Count:   7,
+	}
+	record, err := MarshalRecord(&p)
+	if err != nil {
+		t.Fatalf("MarshalRecord() error: %v", err)
+	}
+	want := []Column{
+		c("Rob, Pike"), c("64"), {}, c("true"), c("-3"), q("x"),
+		c("2021-05-01T12:00:00Z"), c("hi"), c("7"),
+	}
+	if !reflect.DeepEqual(record, want) {
+		t.Errorf("MarshalRecord():\ngot  %v\nwant %v", record, want)
+	}
+
+	header, err := MarshalHeader(person{})
+	if err != nil {
+		t.Fatalf("MarshalHeader() error: %v", err)
+	}
+	wantHeader := []string{"name", "age", "height", "admin", "score", "raw", "created", "note", "Count"}
+	if !reflect.DeepEqual(header, wantHeader) {
+		t.Errorf("MarshalHeader():\ngot  %q\nwant %q", header, wantHeader)
+	}
+
+	var got person
+	if err := UnmarshalRecord(record, &got); err != nil {
+		t.Fatalf("UnmarshalRecord() error: %v", err)
+	}
+	p.Ignored, p.secret = "", ""
+	if !reflect.DeepEqual(got, p) {
+		t.Errorf("round trip:\ngot  %+v\nwant %+v", got, p)
+	}
+
+	if _, err := MarshalRecord(42); err == nil {
+		t.Error("MarshalRecord(42): expected error")
+	}
+	if _, err := MarshalRecord(struct{ C chan int }{}); !errors.Is(err, errUnsupportedType) {
+		t.Errorf("MarshalRecord(chan): error = %v, want %v", err, errUnsupportedType)
+	}
+}
+
+func TestUnmarshalRecord(t *testing.T) {
+	score, five := int8(1), int8(5)
+	tests := []struct {
+		Name   string
+		Header []string
+		Input  []Column
+		Start  person
+		Output person
+		Fail   bool
+		Error  error // Wrapped error, or nil
+	}{{
+		Name:   "Position",
+		Input:  []Column{c("Ken"), c("78"), c("1.8"), c("yes"), c("5")},
+		Output: person{Name: "Ken", Age: 78, Height: 1.8, Admin: true, Score: &five},
+	}, {
+		Name:   "Header",
+		Header: []string{"extra", "age", "name", "Count"},
+		Input:  []Column{c("x"), c("78"), q("Ken"), c("9")},
+		Output: person{Name: "Ken", Age: 78, Count: 9},
+	}, {
+		Name:   "MissingColumns",
+		Input:  []Column{c("Ken")},
+		Start:  person{Age: 1, Score: &score},
+		Output: person{Name: "Ken", Age: 1, Score: &score},
+	}, {
+		Name:   "ExtraColumns",
+		Input:  []Column{c("Ken"), c("1"), c(""), c("0"), c(""), c("r"), c(""), c(""), c("3"), c("extra")},
+		Output: person{Name: "Ken", Age: 1, Raw: c("r"), Count: 3},
+	}, {
+		Name:   "EmptyPointer",
+		Header: []string{"score", "note"},
+		Input:  []Column{c(""), q("")},
+		Start:  person{Score: &score, Audit: Audit{Note: new(string)}},
+		Output: person{},
+	}, {
+		Name:   "EmptyValue",
+		Header: []string{"age", "admin"},
+		Input:  []Column{c(""), c("")},
+		Start:  person{Age: 5, Admin: true},
+		Output: person{},
+	}, {
+		Name:   "InvalidInt",
+		Header: []string{"age"},
+		Input:  []Column{c("old")},
+		Fail:   true,
+		Error:  strconv.ErrSyntax,
+	}, {
+		Name:   "Overflow",
+		Header: []string{"score"},
+		Input:  []Column{c("200")},
+		Fail:   true,
+		Error:  strconv.ErrRange,
+	}, {
+		Name:   "NegativeUint",
+		Header: []string{"Count"},
+		Input:  []Column{c("-1")},
+		Fail:   true,
+		Error:  strconv.ErrSyntax,
+	}, {
+		Name:   "InvalidBool",
+		Header: []string{"admin"},
+		Input:  []Column{c("maybe")},
+		Fail:   true,
+		Error:  strconv.ErrSyntax,
+	}, {
+		Name:   "InvalidTime",
+		Header: []string{"created"},
+		Input:  []Column{c("yesterday")},
+		Fail:   true,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			got := tt.Start
+			var err error
+			if tt.Header != nil {
+				err = UnmarshalRecordHeader(tt.Header, tt.Input, &got)
+			} else {
+				err = UnmarshalRecord(tt.Input, &got)
+			}
+			if tt.Fail {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				if tt.Error != nil && !errors.Is(err, tt.Error) {
+					t.Errorf("error %v does not wrap %v", err, tt.Error)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.Output) {
+				t.Errorf("got  %+v\nwant %+v", got, tt.Output)
+			}
+		})
+	}
+
+	var p person
+	if err := UnmarshalRecord(nil, p); err == nil {
+		t.Error("UnmarshalRecord() into non-pointer: expected error")
+	}
+}
@@ -0,0 +1,177 @@
+package csv
+
+import (
+	"bytes"
+	"io"
+)
+
+// WriteMetrics holds counters describing the output of a Writer.
+type WriteMetrics struct {
+	RecordsWritten   int64 // Records successfully passed to Write
+	BytesWritten     int64 // Bytes forwarded to the underlying io.Writer
+	FlushCount       int   // Flushes, including those done by WriteAll
+	ErrorCount       int   // Errors returned from writes and flushes
+	QuotedFieldCount int64 // Fields enclosed in quotes
+	EmptyFieldCount  int64 // Fields with an empty value
+}
+
+// Metrics returns a snapshot of the Writer's counters.
+// It is safe to call concurrently with the other methods of w.
+func (w *Writer) Metrics() WriteMetrics {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.metrics
+}
+
+// ResetMetrics sets all of the Writer's counters to zero.
+func (w *Writer) ResetMetrics() {
+	w.mu.Lock()
+	w.metrics = WriteMetrics{}
+	w.mu.Unlock()
+}
+
+// countingWriter counts the bytes forwarded to the underlying io.Writer
+// into the metrics of wr. If wr.OmitTrailingNewline is set, it withholds
+// a line terminator at the end of the data written so far until more
+// data follows it.
+type countingWriter struct {
+	w    io.Writer
+	wr   *Writer
+	held []byte // Withheld line terminator
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	if err := c.writeBOM(); err != nil {
+		return 0, err
+	}
+	if !c.wr.OmitTrailingNewline && len(c.held) == 0 {
+		return c.write(p)
+	}
+	buf := make([]byte, 0, len(c.held)+len(p))
+	buf = append(append(buf, c.held...), p...)
+	keep := 0
+	if c.wr.OmitTrailingNewline {
+		keep = terminatorSuffix(buf, c.wr.UseCRLF)
+	}
+	n, err := c.write(buf[:len(buf)-keep])
+	if err != nil {
+		// Report the bytes of p written, if any.
+		if n -= len(c.held); n < 0 {
+			n = 0
+		}
+		return n, err
+	}
+	c.held = append(c.held[:0], buf[len(buf)-keep:]...)
+	return len(p), nil
+}
+
+// write writes p to the underlying io.Writer and counts it.
+func (c *countingWriter) write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.wr.mu.Lock()
+	c.wr.metrics.BytesWritten += int64(n)
+	c.wr.mu.Unlock()
+	return n, err
+}
+
+// terminatorSuffix returns the length of the line terminator at the end
+// of b, counting a final \r as the start of a \r\n terminator if crlf
+// is true.
+func terminatorSuffix(b []byte, crlf bool) int {
+	switch {
+	case crlf && bytes.HasSuffix(b, []byte("\r\n")):
+		return 2
+	case crlf && bytes.HasSuffix(b, []byte("\r")):
+		return 1
+	case !crlf && bytes.HasSuffix(b, []byte("\n")):
+		return 1
+	}
+	return 0
+}
+
+// writeBOM writes the byte order mark if WriteBOM is set and the mark
+// has not been written yet.
+func (c *countingWriter) writeBOM() error {
+	if !c.wr.WriteBOM || c.wr.bomWritten {
+		return nil
+	}
+	c.wr.bomWritten = true
+	n, err := io.WriteString(c.w, utf8BOM)
+	c.wr.mu.Lock()
+	c.wr.metrics.BytesWritten += int64(n)
+	c.wr.mu.Unlock()
+	return err
+}
+
+// ReadMetrics holds counters describing the input consumed by a Reader.
+type ReadMetrics struct {
+	RecordsRead         int64 // Records returned by Read and ReadAll
+	BytesRead           int64 // Bytes consumed from the underlying io.Reader
+	CommentLinesSkipped int   // Lines skipped because they begin with Comment
+	BlankLinesSkipped   int   // Empty lines skipped between records
+	ParseErrors         int   // ParseErrors returned
+	QuotedFieldCount    int64 // Fields that were quoted in the input
+	MaxFieldLength      int   // Length in bytes of the longest field value
+}
+
+// Metrics returns a snapshot of the Reader's counters.
+// It is safe to call concurrently with the other methods of r.
+func (r *Reader) Metrics() ReadMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
+
+// ResetMetrics sets all of the Reader's counters to zero.
+func (r *Reader) ResetMetrics() {
+	r.mu.Lock()
+	r.metrics = ReadMetrics{}
+	r.mu.Unlock()
+}
+
+// updateMetrics adds the counters of a record returned by parseRecord
+// to the Reader's metrics.
+func (r *Reader) updateMetrics(record []Column, err error) {
+	p := r.pending
+	r.pending = ReadMetrics{}
+	perr, ok := err.(*ParseError)
+	if ok {
+		p.ParseErrors++
+	}
+	// Records come with a ParseError only for ErrFieldCount, and are
+	// returned along with every ValidationError.
+	_, invalid := err.(*ValidationError)
+	if record != nil && (err == nil || invalid || ok && perr.Err == ErrFieldCount) {
+		p.RecordsRead++
+		for _, col := range record {
+			if col.Quoted {
+				p.QuotedFieldCount++
+			}
+			if len(col.Value) > p.MaxFieldLength {
+				p.MaxFieldLength = len(col.Value)
+			}
+		}
+	}
+
+	r.addMetrics(p)
+}
+
+// addMetrics adds the counters of p to the Reader's metrics.
+func (r *Reader) addMetrics(p ReadMetrics) {
+	r.mu.Lock()
+	r.metrics.add(p)
+	r.mu.Unlock()
+}
+
+// add adds the counters of p to m.
+func (m *ReadMetrics) add(p ReadMetrics) {
+	m.RecordsRead += p.RecordsRead
+	m.BytesRead += p.BytesRead
+	m.CommentLinesSkipped += p.CommentLinesSkipped
+	m.BlankLinesSkipped += p.BlankLinesSkipped
+	m.ParseErrors += p.ParseErrors
+	m.QuotedFieldCount += p.QuotedFieldCount
+	if p.MaxFieldLength > m.MaxFieldLength {
+		m.MaxFieldLength = p.MaxFieldLength
+	}
+}
@@ -0,0 +1,86 @@
+package csv
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteMetrics(t *testing.T) {
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	records := [][]Column{
+		{{Value: "a"}, {Value: ""}, {Value: "b,c"}},
+		{{Value: "d", Quoted: true}, {Value: ""}, {Value: ""}},
+	}
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	w.Write([]Column{{Value: "e"}})
+	w.Flush()
+
+	want := WriteMetrics{
+		RecordsWritten:   3,
+		BytesWritten:     int64(b.Len()),
+		FlushCount:       2,
+		QuotedFieldCount: 2,
+		EmptyFieldCount:  3,
+	}
+	if got := w.Metrics(); got != want {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	w.ResetMetrics()
+	if got := w.Metrics(); got != (WriteMetrics{}) {
+		t.Errorf("Metrics() after ResetMetrics = %+v, want zero", got)
+	}
+
+	w = NewWriter(errorWriter{})
+	w.Write([]Column{{Value: "abc"}})
+	w.Flush()
+	if got := w.Metrics(); got.ErrorCount != 1 || got.BytesWritten != 0 {
+		t.Errorf("Metrics() on failing writer = %+v, want ErrorCount 1 and no bytes", got)
+	}
+}
+
+func TestReadMetrics(t *testing.T) {
+	// Same input as the HugeLines case of TestRead.
+	input := strings.Repeat("#ignore\n", 10000) + strings.Repeat("@", 5000) + "," + strings.Repeat("*", 5000)
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	if _, err := r.ReadAll(); err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := ReadMetrics{
+		RecordsRead:         1,
+		BytesRead:           int64(len(input)),
+		CommentLinesSkipped: 10000,
+		MaxFieldLength:      5000,
+	}
+	if got := r.Metrics(); got != want {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	r = NewReader(strings.NewReader("a,\"b\"\n\n\nc,d\ne,\"f\n"))
+	for {
+		if _, err := r.Read(); err != nil {
+			break
+		}
+	}
+	want = ReadMetrics{
+		RecordsRead:       2,
+		BytesRead:         17,
+		BlankLinesSkipped: 2,
+		ParseErrors:       1,
+		QuotedFieldCount:  1,
+		MaxFieldLength:    1,
+	}
+	if got := r.Metrics(); got != want {
+		t.Errorf("Metrics() = %+v, want %+v", got, want)
+	}
+
+	r.ResetMetrics()
+	if got := r.Metrics(); got != (ReadMetrics{}) {
+		t.Errorf("Metrics() after ResetMetrics = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+package csv
+
+import "os"
+
+// NewMmapReader returns a Reader reading the named file. On this platform
+// the file is opened normally, and the returned function closes it.
+func NewMmapReader(path string) (*Reader, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewReader(f), func() { f.Close() }, nil
+}
@@ -0,0 +1,65 @@
+package csv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewMmapReader(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 50000; i++ {
+		fmt.Fprintf(&b, "%d,\"name %d\",\"multi\nline, \"\"%d\"\"\"\r\n", i, i, i*7)
+	}
+	path := filepath.Join(t.TempDir(), "large.csv")
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	want, err := NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+
+	r, cleanup, err := NewMmapReader(path)
+	if err != nil {
+		t.Fatalf("NewMmapReader() error: %v", err)
+	}
+	got, err := r.ReadAll()
+	cleanup()
+	if err != nil {
+		t.Fatalf("ReadAll() from mapping error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll() from mapping differs from ReadAll() from file")
+	}
+	if len(got) != 50000 || got[49999][2].Value != "multi\nline, \"349993\"" {
+		t.Errorf("ReadAll() from mapping read %d records, last %v", len(got), got[len(got)-1])
+	}
+	cleanup() // Calling cleanup twice is harmless.
+
+	empty := filepath.Join(t.TempDir(), "empty.csv")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r, cleanup, err = NewMmapReader(empty)
+	if err != nil {
+		t.Fatalf("NewMmapReader() of empty file error: %v", err)
+	}
+	defer cleanup()
+	if records, err := r.ReadAll(); err != nil || records != nil {
+		t.Errorf("ReadAll() of empty file = %v, %v, want nil, nil", records, err)
+	}
+
+	if _, _, err := NewMmapReader(filepath.Join(t.TempDir(), "missing.csv")); !os.IsNotExist(err) {
+		t.Errorf("NewMmapReader() of missing file error = %v, want not exist", err)
+	}
+}
@@ -0,0 +1,50 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+package csv
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+)
+
+// NewMmapReader returns a Reader reading the named file through a
+// read-only memory mapping, which avoids copying the file contents
+// through the kernel's read path. It is the recommended way to read
+// files of more than about 1 GB. The returned function unmaps the file.
+// It must be called once the Reader is no longer used; records already
+// read do not refer to the mapping and stay valid.
+//
+// On platforms without mmap support, NewMmapReader opens the file
+// normally and the returned function closes it.
+func NewMmapReader(path string) (*Reader, func(), error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, nil, err
+	}
+	size := fi.Size()
+	if size == 0 {
+		// Empty files cannot be mapped.
+		return NewReader(bytes.NewReader(nil)), func() {}, nil
+	}
+	if int64(int(size)) != size {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: syscall.EFBIG}
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: path, Err: err}
+	}
+	cleanup := func() {
+		if data != nil {
+			syscall.Munmap(data)
+			data = nil
+		}
+	}
+	return NewReader(bytes.NewReader(data)), cleanup, nil
+}
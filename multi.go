@@ -0,0 +1,99 @@
+package csv
+
+import "io"
+
+// A MultiReader reads the records of several CSV sources with the same
+// header row as if they were a single file. The header row of the first
+// source is returned as the first record; the header rows of the other
+// sources are skipped after checking that they equal the first one.
+type MultiReader struct {
+	sources []*Reader
+	cur     int
+
+	header []Column
+	// inHeader is true while the header row of sources[cur] is unread.
+	inHeader bool
+	// lineOffset is the number of lines of the sources before sources[cur].
+	lineOffset int
+}
+
+// NewMultiReader returns a MultiReader reading from readers in order.
+func NewMultiReader(readers ...*Reader) *MultiReader {
+	return &MultiReader{sources: readers, inHeader: true}
+}
+
+// CurrentSource returns the index of the reader that Read is reading from.
+// Once all readers are exhausted, it returns the number of readers.
+func (m *MultiReader) CurrentSource() int {
+	return m.cur
+}
+
+// Read reads one record from the current source, moving on to the next
+// source once it is exhausted. If there is no data left in any source,
+// Read returns nil, io.EOF.
+//
+// Line numbers in ParseErrors and ValidationErrors count the lines of all
+// sources, as if they were concatenated. If the header row of a source
+// differs from that of the first source, Read returns a ParseError
+// wrapping ErrHeaderMismatch; the following call to Read continues with
+// the first record after that header row.
+func (m *MultiReader) Read() ([]Column, error) {
+	for m.cur < len(m.sources) {
+		r := m.sources[m.cur]
+		record, err := r.Read()
+		if err == io.EOF {
+			m.lineOffset += r.numLine - 1
+			m.cur++
+			m.inHeader = true
+			continue
+		}
+		if err != nil {
+			return record, m.adjustLines(err)
+		}
+		if !m.inHeader {
+			return record, nil
+		}
+
+		m.inHeader = false
+		if m.header == nil {
+			m.header = append([]Column(nil), record...)
+			return record, nil
+		}
+		if !sameHeader(record, m.header) {
+			line := m.lineOffset + r.recLine
+			return nil, &ParseError{StartLine: line, Line: line, Err: ErrHeaderMismatch}
+		}
+	}
+	return nil, io.EOF
+}
+
+// adjustLines returns err with its line numbers counted from the start
+// of the first source.
+func (m *MultiReader) adjustLines(err error) error {
+	switch e := err.(type) {
+	case *ParseError:
+		c := *e
+		c.StartLine += m.lineOffset
+		c.Line += m.lineOffset
+		return &c
+	case *ValidationError:
+		c := *e
+		c.StartLine += m.lineOffset
+		c.Line += m.lineOffset
+		return &c
+	}
+	return err
+}
+
+// sameHeader reports whether a and b hold the same values.
+func sameHeader(a, b []Column) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Value != b[i].Value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,83 @@
+package csv
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newReaders(inputs ...string) []*Reader {
+	readers := make([]*Reader, len(inputs))
+	for i, in := range inputs {
+		readers[i] = NewReader(strings.NewReader(in))
+	}
+	return readers
+}
+
+func TestMultiReader(t *testing.T) {
+	m := NewMultiReader(newReaders(
+		"",
+		"name,lang\nRob,go\n",
+		"name,lang\n",
+		"\n# not a comment\n",
+		"name,lang\nKen,C\nDennis,C",
+	)...)
+	// The fourth source has a different header.
+	type result struct {
+		record []Column
+		source int
+		err    error
+	}
+	var got []result
+	for {
+		record, err := m.Read()
+		if err == io.EOF {
+			break
+		}
+		got = append(got, result{record, m.CurrentSource(), err})
+		if len(got) > 10 {
+			t.Fatal("too many records")
+		}
+	}
+	want := []result{
+		{[]Column{c("name"), c("lang")}, 1, nil},
+		{[]Column{c("Rob"), c("go")}, 1, nil},
+		{nil, 3, &ParseError{StartLine: 5, Line: 5, Err: ErrHeaderMismatch}},
+		{[]Column{c("Ken"), c("C")}, 4, nil},
+		{[]Column{c("Dennis"), c("C")}, 4, nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() results:\ngot  %v\nwant %v", got, want)
+	}
+	if n := m.CurrentSource(); n != 5 {
+		t.Errorf("CurrentSource() = %d at EOF, want 5", n)
+	}
+}
+
+func TestMultiReaderLines(t *testing.T) {
+	sources := newReaders("a,b\n1,2\n3,4\n", "a,b\n\n5,6\n7,8\"\n", "a,b\n9,10")
+	m := NewMultiReader(sources...)
+	for i := 0; i < 4; i++ {
+		if _, err := m.Read(); err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+	}
+	// The second source is 4 lines long; the error is on its 4th line.
+	_, err := m.Read()
+	want := &ParseError{StartLine: 7, Line: 7, Column: 3, Err: ErrBareQuote}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Read() error = %v, want %v", err, want)
+	}
+
+	record, err := m.Read()
+	if err != nil || !reflect.DeepEqual(record, []Column{c("9"), c("10")}) {
+		t.Errorf("Read() = %v, %v, want [9 10]", record, err)
+	}
+	if _, err := m.Read(); err != io.EOF {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+	if _, err := NewMultiReader().Read(); err != io.EOF {
+		t.Errorf("Read() without sources: error = %v, want io.EOF", err)
+	}
+}
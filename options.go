@@ -0,0 +1,102 @@
+package csv
+
+import "io"
+
+// A ReaderOption configures a Reader created by NewReaderWithOptions.
+type ReaderOption func(*Reader)
+
+// WithComma sets Reader.Comma.
+func WithComma(comma rune) ReaderOption {
+	return func(r *Reader) { r.Comma = comma }
+}
+
+// WithComment sets Reader.Comment.
+func WithComment(comment rune) ReaderOption {
+	return func(r *Reader) { r.Comment = comment }
+}
+
+// WithLazyQuotes sets Reader.LazyQuotes.
+func WithLazyQuotes(lazy bool) ReaderOption {
+	return func(r *Reader) { r.LazyQuotes = lazy }
+}
+
+// WithTrimLeadingSpace sets Reader.TrimLeadingSpace.
+func WithTrimLeadingSpace(trim bool) ReaderOption {
+	return func(r *Reader) { r.TrimLeadingSpace = trim }
+}
+
+// WithFieldsPerRecord sets Reader.FieldsPerRecord.
+func WithFieldsPerRecord(n int) ReaderOption {
+	return func(r *Reader) { r.FieldsPerRecord = n }
+}
+
+// WithReuseRecord sets Reader.ReuseRecord.
+func WithReuseRecord(reuse bool) ReaderOption {
+	return func(r *Reader) { r.ReuseRecord = reuse }
+}
+
+// NewReaderWithOptions returns a new Reader reading from r, configured by
+// applying opts in order to a Reader as returned by NewReader. It returns
+// an error if the resulting settings are invalid, such as a Comment equal
+// to Comma, which Read would otherwise report on the first call.
+func NewReaderWithOptions(r io.Reader, opts ...ReaderOption) (*Reader, error) {
+	rd := NewReader(r)
+	for _, opt := range opts {
+		opt(rd)
+	}
+	if err := rd.checkConfig(); err != nil {
+		return nil, err
+	}
+	return rd, nil
+}
+
+// A WriterOption configures a Writer created by NewWriterWithOptions.
+type WriterOption func(*Writer)
+
+// WithWriterComma sets Writer.Comma.
+func WithWriterComma(comma rune) WriterOption {
+	return func(w *Writer) { w.Comma = comma }
+}
+
+// WithWriterComment sets Writer.Comment.
+func WithWriterComment(comment rune) WriterOption {
+	return func(w *Writer) { w.Comment = comment }
+}
+
+// WithQuote sets Writer.Quote.
+func WithQuote(quote rune) WriterOption {
+	return func(w *Writer) { w.Quote = quote }
+}
+
+// WithUseCRLF sets Writer.UseCRLF.
+func WithUseCRLF(crlf bool) WriterOption {
+	return func(w *Writer) { w.UseCRLF = crlf }
+}
+
+// WithQuoteStyle sets Writer.QuoteStyle.
+func WithQuoteStyle(style QuoteStyle) WriterOption {
+	return func(w *Writer) { w.QuoteStyle = style }
+}
+
+// WithAutoFlush sets Writer.AutoFlush.
+func WithAutoFlush(autoFlush bool) WriterOption {
+	return func(w *Writer) { w.AutoFlush = autoFlush }
+}
+
+// NewWriterWithOptions returns a new Writer writing to w, configured by
+// applying opts in order to a Writer as returned by NewWriter. It returns
+// an error if the resulting delimiters are invalid, which Write would
+// otherwise report on the first call.
+func NewWriterWithOptions(w io.Writer, opts ...WriterOption) (*Writer, error) {
+	wr := NewWriter(w)
+	for _, opt := range opts {
+		opt(wr)
+	}
+	if err := wr.checkConfig(); err != nil {
+		return nil, err
+	}
+	if wr.Comment != 0 && (wr.Comment == wr.Comma || wr.Comment == wr.Quote || !validDelim(wr.Comment)) {
+		return nil, errInvalidDelim
+	}
+	return wr, nil
+}
@@ -0,0 +1,72 @@
+package csv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewReaderWithOptions(t *testing.T) {
+	r, err := NewReaderWithOptions(strings.NewReader("# skip\n a;\"b\"\nc\"d;e\n"),
+		WithComma(';'),
+		WithComment('#'),
+		WithLazyQuotes(true),
+		WithTrimLeadingSpace(true),
+		WithFieldsPerRecord(2),
+		WithReuseRecord(true),
+	)
+	if err != nil {
+		t.Fatalf("NewReaderWithOptions() error: %v", err)
+	}
+	if !r.ReuseRecord {
+		t.Errorf("WithReuseRecord(true) did not set ReuseRecord")
+	}
+	got, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := [][]Column{{c("a"), q("b")}, {c(`c"d`), c("e")}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAll() = %v, want %v", got, want)
+	}
+
+	for _, opts := range [][]ReaderOption{
+		{WithComma(';'), WithComment(';')},
+		{WithComment(',')},
+		{WithComma('\n')},
+	} {
+		if r, err := NewReaderWithOptions(strings.NewReader(""), opts...); err != errInvalidDelim || r != nil {
+			t.Errorf("NewReaderWithOptions() = %v, %v, want nil, %v", r, err, errInvalidDelim)
+		}
+	}
+}
+
+func TestNewWriterWithOptions(t *testing.T) {
+	var b strings.Builder
+	w, err := NewWriterWithOptions(&b,
+		WithWriterComma('\t'),
+		WithWriterComment('#'),
+		WithQuote('\''),
+		WithUseCRLF(true),
+		WithQuoteStyle(QuoteNonNumeric),
+		WithAutoFlush(true),
+	)
+	if err != nil {
+		t.Fatalf("NewWriterWithOptions() error: %v", err)
+	}
+	w.WriteComment("note")
+	w.Write([]Column{c("1"), c("a\tb")})
+	if got, want := b.String(), "#note\r\n1\t'a\tb'\r\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+
+	for _, opts := range [][]WriterOption{
+		{WithWriterComma('|'), WithQuote('|')},
+		{WithWriterComment(',')},
+		{WithWriterComma(0)},
+	} {
+		if w, err := NewWriterWithOptions(&b, opts...); err != errInvalidDelim || w != nil {
+			t.Errorf("NewWriterWithOptions() = %v, %v, want nil, %v", w, err, errInvalidDelim)
+		}
+	}
+}
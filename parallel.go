@@ -0,0 +1,208 @@
+package csv
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// chunksPerWorker is the number of chunks the input of ReadAllParallel is
+// split into per worker, so that uneven chunks still keep workers busy.
+const chunksPerWorker = 4
+
+// ReadAllParallel is like ReadAll but parses the input with up to
+// concurrency goroutines. If concurrency is not positive,
+// runtime.GOMAXPROCS(0) is used.
+//
+// The remaining input is read into memory and split into chunks at line
+// breaks outside of quoted fields, which are parsed concurrently and
+// merged in order. Line numbers in errors refer to the whole input.
+// Unescape and transforms added by AddTransformer are called from several
+// goroutines at once and must be safe for concurrent use.
+//
+// If r has a schema attached, an error handler set or AllowBareNewlines
+// enabled, ReadAllParallel reads sequentially like ReadAll.
+func (r *Reader) ReadAllParallel(concurrency int) (records [][]Column, err error) {
+	if r.schema != nil || r.errorHandler != nil || r.AllowBareNewlines {
+		return r.ReadAll()
+	}
+	if concurrency <= 0 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	// Records parsed by Peek come first, and the first record settles
+	// the number of fields if FieldsPerRecord is 0.
+	for len(r.peeked) > 0 || r.FieldsPerRecord == 0 {
+		record, err := r.readRecord(nil)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	data, err := io.ReadAll(r.r)
+	if err != nil {
+		return nil, err
+	}
+	var bomLen int
+	if r.StripBOM && !r.bomChecked && bytes.HasPrefix(data, []byte(utf8BOM)) {
+		bomLen = len(utf8BOM)
+	}
+	r.bomChecked = true
+	r.offset += int64(len(data))
+	body := data[bomLen:]
+
+	bounds := r.chunkBounds(body, concurrency*chunksPerWorker)
+	type result struct {
+		records  [][]Column
+		err      error
+		metrics  ReadMetrics
+		comments []string
+	}
+	results := make([]result, len(bounds))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(bounds); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for k := range jobs {
+				start := 0
+				if k > 0 {
+					start = bounds[k-1]
+				}
+				cr := r.chunkReader(body[start:bounds[k]])
+				res := &results[k]
+				res.records, res.err = cr.ReadAll()
+				res.metrics = cr.metrics
+				res.comments = cr.Comments
+			}
+		}()
+	}
+	for k := range bounds {
+		jobs <- k
+	}
+	close(jobs)
+	wg.Wait()
+
+	var m ReadMetrics
+	m.BytesRead = int64(bomLen)
+	line, start := r.numLine, 0
+	for k, res := range results {
+		m.add(res.metrics)
+		r.Comments = append(r.Comments, res.comments...)
+		if res.err != nil {
+			r.addMetrics(m)
+			return nil, adjustLines(res.err, line)
+		}
+		records = append(records, res.records...)
+		line += bytes.Count(body[start:bounds[k]], []byte{'\n'})
+		start = bounds[k]
+	}
+	if len(body) > 0 && body[len(body)-1] != '\n' {
+		line++ // Final line without line break
+	}
+	r.numLine = line
+	r.addMetrics(m)
+	return records, nil
+}
+
+// chunkReader returns a Reader parsing chunk with the settings of r.
+func (r *Reader) chunkReader(chunk []byte) *Reader {
+	cr := NewReader(bytes.NewReader(chunk))
+	cr.Comma = r.Comma
+	cr.Quote = r.Quote
+	cr.Comment = r.Comment
+	cr.FieldsPerRecord = r.FieldsPerRecord
+	cr.LazyQuotes = r.LazyQuotes
+	cr.TrimLeadingSpace = r.TrimLeadingSpace
+	cr.TrimTrailingSpace = r.TrimTrailingSpace
+	cr.MergeConsecutiveDelimiters = r.MergeConsecutiveDelimiters
+	cr.NormalizeCRLF = r.NormalizeCRLF
+	cr.SanitizeFields = r.SanitizeFields
+	cr.NullValue = r.NullValue
+	cr.EscapeChar = r.EscapeChar
+	cr.DisableDoubleQuoteEscape = r.DisableDoubleQuoteEscape
+	cr.MaxFieldSize = r.MaxFieldSize
+	cr.Unescape = r.Unescape
+	cr.IgnorePanic = r.IgnorePanic
+	cr.CaptureRaw = r.CaptureRaw
+	cr.CollectComments = r.CollectComments
+	cr.transformers = r.transformers
+	cr.fieldNames = r.fieldNames
+	return cr
+}
+
+// adjustLines returns err with the line numbers of a ParseError moved down
+// by offset lines.
+func adjustLines(err error, offset int) error {
+	if e, ok := err.(*ParseError); ok {
+		c := *e
+		c.StartLine += offset
+		c.Line += offset
+		return &c
+	}
+	return err
+}
+
+// chunkBounds splits data into about n chunks of similar size, ending at
+// line breaks outside of quoted fields, and returns the end offset of each.
+// The scan follows the quoting rules of the parser closely enough to find
+// record boundaries in valid input; in invalid input, a record may be
+// split, but its error is still reported.
+func (r *Reader) chunkBounds(data []byte, n int) []int {
+	target := len(data)/n + 1
+	var bounds []int
+	next := target // Offset from which to end the current chunk
+	inQuotes, fieldStart, lineStart := false, true, true
+	for i := 0; i < len(data); {
+		rn, size := utf8.DecodeRune(data[i:])
+		switch {
+		case inQuotes:
+			switch {
+			case r.EscapeChar != 0 && rn == r.EscapeChar:
+				_, w := utf8.DecodeRune(data[i+size:])
+				size += w
+			case rn == r.Quote:
+				after, w := utf8.DecodeRune(data[i+size:])
+				switch {
+				case after == r.Quote:
+					size += w // Doubled quote, kept as two with DisableDoubleQuoteEscape
+				case i+size == len(data), after == r.Comma, after == '\n', after == '\r':
+					inQuotes = false
+				case !r.LazyQuotes:
+					inQuotes = false // Invalid; parsing fails here anyway
+				}
+			}
+		case lineStart && r.Comment != 0 && rn == r.Comment:
+			if j := bytes.IndexByte(data[i:], '\n'); j >= 0 {
+				size = j // Skip to the line break
+			} else {
+				size = len(data) - i
+			}
+		case rn == '\n':
+			if i+size >= next && i+size < len(data) {
+				bounds = append(bounds, i+size)
+				next = i + size + target
+			}
+			fieldStart, lineStart = true, true
+			i += size
+			continue
+		case rn == r.Comma:
+			fieldStart = true
+		case rn == r.Quote && fieldStart:
+			inQuotes, fieldStart = true, false
+		case r.TrimLeadingSpace && fieldStart && unicode.IsSpace(rn):
+		default:
+			fieldStart = false
+		}
+		lineStart = false
+		i += size
+	}
+	return append(bounds, len(data))
+}
@@ -0,0 +1,127 @@
+package csv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// parallelInput returns n records with quoted, multi-line and escaped
+// fields, interspersed with comments and blank lines.
+func parallelInput(n int) string {
+	var b strings.Builder
+	b.WriteString("\ufeffid,name,note\r\n")
+	for i := 0; i < n; i++ {
+		switch i % 5 {
+		case 0:
+			fmt.Fprintf(&b, "%d,plain,text\n", i)
+		case 1:
+			fmt.Fprintf(&b, "%d,\"multi\nline, \"\"quoted\"\"\n\",x\r\n", i)
+		case 2:
+			fmt.Fprintf(&b, "# comment with \" quote %d\n\n", i)
+			fmt.Fprintf(&b, "%d,\"\",\"\"\"\n\"\"\"\n", i)
+		case 3:
+			fmt.Fprintf(&b, "%d,ünï,\"çödé\r\nwith CRLF\"\n", i)
+		case 4:
+			fmt.Fprintf(&b, "%d,\"a,b\",\"\n\"\n", i)
+		}
+	}
+	return b.String()
+}
+
+func TestReadAllParallel(t *testing.T) {
+	input := parallelInput(500)
+	newReader := func() *Reader {
+		r := NewReader(strings.NewReader(input))
+		r.Comment = '#'
+		r.StripBOM = true
+		r.CollectComments = true
+		return r
+	}
+	ref := newReader()
+	want, err := ref.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	for _, n := range []int{0, 1, 2, 3, 8, 64} {
+		r := newReader()
+		got, err := r.ReadAllParallel(n)
+		if err != nil {
+			t.Fatalf("ReadAllParallel(%d) error: %v", n, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("ReadAllParallel(%d) differs from ReadAll()", n)
+		}
+		if got, want := r.Metrics(), ref.Metrics(); got != want {
+			t.Errorf("ReadAllParallel(%d) metrics:\ngot  %+v\nwant %+v", n, got, want)
+		}
+		if !reflect.DeepEqual(r.Comments, ref.Comments) {
+			t.Errorf("ReadAllParallel(%d) comments differ from ReadAll()", n)
+		}
+	}
+
+	// Records read by Peek are returned first.
+	r := newReader()
+	r.FieldsPerRecord = 3
+	if _, err := r.Peek(2); err != nil {
+		t.Fatalf("Peek() error: %v", err)
+	}
+	got, err := r.ReadAllParallel(4)
+	if err != nil {
+		t.Fatalf("ReadAllParallel() after Peek error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadAllParallel() after Peek differs from ReadAll()")
+	}
+}
+
+func TestReadAllParallelError(t *testing.T) {
+	var b strings.Builder
+	for i := 0; i < 300; i++ {
+		if i == 250 {
+			b.WriteString("\"multi\nline\",x\"y\n")
+			continue
+		}
+		fmt.Fprintf(&b, "%d,\"%d\"\n", i, i)
+	}
+	input := b.String()
+	_, want := NewReader(strings.NewReader(input)).ReadAll()
+	if want == nil {
+		t.Fatal("ReadAll() succeeded, want error")
+	}
+	for _, n := range []int{1, 4, 16} {
+		_, err := NewReader(strings.NewReader(input)).ReadAllParallel(n)
+		if !reflect.DeepEqual(err, want) {
+			t.Errorf("ReadAllParallel(%d) error = %v, want %v", n, err, want)
+		}
+	}
+
+	r := NewReader(strings.NewReader("a,b\nc\n"))
+	if _, err := r.ReadAllParallel(2); !reflect.DeepEqual(err, &ParseError{StartLine: 2, Line: 2, Err: ErrFieldCount}) {
+		t.Errorf("ReadAllParallel() error = %v, want ErrFieldCount on line 2", err)
+	}
+}
+
+func TestChunkBounds(t *testing.T) {
+	r := NewReader(nil)
+	r.Comment = '#'
+	data := []byte("a,\"b\nc\"\n# \"\nd,e\"f\ng,\"h\"\"\ni\"\nj\n")
+	bounds := r.chunkBounds(data, len(data))
+	want := []int{8, 12, 18, 28, 30}
+	if !reflect.DeepEqual(bounds, want) {
+		t.Errorf("chunkBounds() = %v, want %v", bounds, want)
+	}
+}
+
+func BenchmarkReadAllParallel(b *testing.B) {
+	input := parallelInput(20000)
+	b.SetBytes(int64(len(input)))
+	for i := 0; i < b.N; i++ {
+		r := NewReader(strings.NewReader(input))
+		r.Comment = '#'
+		if _, err := r.ReadAllParallel(0); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
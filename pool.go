@@ -0,0 +1,60 @@
+package csv
+
+import "sync"
+
+// A ColumnPool recycles the slices records are stored in, to save their
+// allocation when reading many records. Attach it to a Reader with
+// SetPool, and hand each record back with Put once it is no longer used.
+// The zero value is an empty pool ready to use. A ColumnPool is safe for
+// concurrent use by multiple goroutines.
+type ColumnPool struct {
+	records sync.Pool // *pooledRecord holding a slice
+	holders sync.Pool // *pooledRecord holding nothing
+}
+
+// A pooledRecord holds a slice in a ColumnPool. Keeping slices behind a
+// reused pointer avoids allocating an interface value on every Put.
+type pooledRecord struct {
+	record []Column
+}
+
+// Get returns a slice of length 0 from the pool, or nil if the pool is
+// empty.
+func (p *ColumnPool) Get() []Column {
+	h, _ := p.records.Get().(*pooledRecord)
+	if h == nil {
+		return nil
+	}
+	record := h.record
+	h.record = nil
+	p.holders.Put(h)
+	return record[:0]
+}
+
+// Put adds record to the pool. The caller must not use record or any of
+// its fields' backing arrays afterwards. Slices of capacity 0 are dropped.
+func (p *ColumnPool) Put(record []Column) {
+	if cap(record) == 0 {
+		return
+	}
+	h, _ := p.holders.Get().(*pooledRecord)
+	if h == nil {
+		h = new(pooledRecord)
+	}
+	// Clear the fields so that the pool does not keep their data alive.
+	record = record[:cap(record)]
+	for i := range record {
+		record[i] = Column{}
+	}
+	h.record = record
+	p.records.Put(h)
+}
+
+// SetPool makes Read store records in slices taken from p, which the
+// caller returns with p.Put once done with each record. Passing nil
+// stops using a pool. SetPool has no effect while ReuseRecord is true
+// or RecordPrealloc is set, and does not apply to the other methods
+// reading several records at once, such as ReadAll.
+func (r *Reader) SetPool(p *ColumnPool) {
+	r.pool = p
+}
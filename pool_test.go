@@ -0,0 +1,89 @@
+package csv
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnPool(t *testing.T) {
+	var p ColumnPool
+	if got := p.Get(); got != nil {
+		t.Errorf("Get() on empty pool = %v, want nil", got)
+	}
+	p.Put(nil) // Dropped
+
+	p.Put([]Column{c("a"), c("b")})
+	got := p.Get()
+	if got == nil {
+		// sync.Pool may drop items at any time, for example under the
+		// race detector.
+		t.Skip("pool dropped the slice")
+	}
+	if len(got) != 0 || cap(got) != 2 {
+		t.Errorf("Get() = len %d, cap %d, want 0, 2", len(got), cap(got))
+	}
+	if full := got[:2]; !reflect.DeepEqual(full, []Column{{}, {}}) {
+		t.Errorf("Get() kept old fields %v", full)
+	}
+}
+
+func TestReaderSetPool(t *testing.T) {
+	var p ColumnPool
+	r := NewReader(strings.NewReader("a,b\nc,d\n"))
+	r.SetPool(&p)
+	var records [][]Column
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		records = append(records, append([]Column(nil), record...))
+		p.Put(record)
+	}
+	want := [][]Column{{c("a"), c("b")}, {c("c"), c("d")}}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("Read() = %v, want %v", records, want)
+	}
+
+	// A warm pool saves the allocation of the record slice, leaving only
+	// the string data of the fields.
+	allocs := func(p *ColumnPool) float64 {
+		r := NewReader(&nTimes{s: "xx,yy,zz\n", n: 1000})
+		r.SetPool(p)
+		return testing.AllocsPerRun(100, func() {
+			record, _ := r.Read()
+			if p != nil {
+				p.Put(record)
+			}
+		})
+	}
+	plain, pooled := allocs(nil), allocs(new(ColumnPool))
+	if pooled > plain*0.7 {
+		t.Errorf("Read() with pool: %v allocs per record, want at most 70%% of %v", pooled, plain)
+	}
+}
+
+func BenchmarkReadPool(b *testing.B) {
+	var p ColumnPool
+	for i := 0; i < 16; i++ {
+		p.Put(make([]Column, 4))
+	}
+	b.ReportAllocs()
+	r := NewReader(&nTimes{s: benchmarkCSVData, n: b.N})
+	r.SetPool(&p)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			b.Fatal(err)
+		}
+		p.Put(record)
+	}
+}
@@ -56,9 +56,12 @@ package csv
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 )
@@ -66,36 +69,67 @@ import (
 // A ParseError is returned for parsing errors.
 // Line numbers are 1-indexed and columns are 0-indexed.
 type ParseError struct {
-	StartLine int   // Line where the record starts
-	Line      int   // Line where the error occurred
-	Column    int   // Column (rune index) where the error occurred
-	Err       error // The actual error
+	StartLine int    // Line where the record starts
+	Line      int    // Line where the error occurred
+	Column    int    // Column (rune index) where the error occurred
+	FieldName string // Name of the field where the error occurred, if known
+	Err       error  // The actual error
 }
 
 func (e *ParseError) Error() string {
 	if e.Err == ErrFieldCount {
 		return fmt.Sprintf("record on line %d: %v", e.Line, e.Err)
 	}
+	err := fmt.Sprint(e.Err)
+	if e.FieldName != "" {
+		err = fmt.Sprintf("field %q: %s", e.FieldName, err)
+	}
 	if e.StartLine != e.Line {
-		return fmt.Sprintf("record on line %d; parse error on line %d, column %d: %v", e.StartLine, e.Line, e.Column, e.Err)
+		return fmt.Sprintf("record on line %d; parse error on line %d, column %d: %s", e.StartLine, e.Line, e.Column, err)
 	}
-	return fmt.Sprintf("parse error on line %d, column %d: %v", e.Line, e.Column, e.Err)
+	return fmt.Sprintf("parse error on line %d, column %d: %s", e.Line, e.Column, err)
 }
 
 func (e *ParseError) Unwrap() error { return e.Err }
 
+// Is reports whether target is a *ParseError matching e. Fields of target
+// that are zero match any value; a non-nil target.Err matches if
+// errors.Is(e.Err, target.Err). For example, the following reports
+// whether err is a ParseError on line 3 wrapping ErrQuote:
+//
+//	errors.Is(err, &csv.ParseError{Line: 3, Err: csv.ErrQuote})
+func (e *ParseError) Is(target error) bool {
+	t, ok := target.(*ParseError)
+	if !ok {
+		return false
+	}
+	return (t.StartLine == 0 || t.StartLine == e.StartLine) &&
+		(t.Line == 0 || t.Line == e.Line) &&
+		(t.Column == 0 || t.Column == e.Column) &&
+		(t.FieldName == "" || t.FieldName == e.FieldName) &&
+		(t.Err == nil || errors.Is(e.Err, t.Err))
+}
+
 // These are the errors that can be returned in ParseError.Err.
 var (
-	ErrTrailingComma = errors.New("extra delimiter at end of line") // Deprecated: No longer used.
-	ErrBareQuote     = errors.New("bare \" in non-quoted-field")
-	ErrQuote         = errors.New("extraneous or missing \" in quoted-field")
-	ErrFieldCount    = errors.New("wrong number of fields")
+	ErrTrailingComma  = errors.New("extra delimiter at end of line") // Deprecated: No longer used.
+	ErrBareQuote      = errors.New("bare \" in non-quoted-field")
+	ErrQuote          = errors.New("extraneous or missing \" in quoted-field")
+	ErrFieldCount     = errors.New("wrong number of fields")
+	ErrHookPanic      = errors.New("panic in hook function")
+	ErrFieldTooLarge  = errors.New("field exceeds MaxFieldSize")
+	ErrHeaderMismatch = errors.New("header differs from first source")
 )
 
+// utf8BOM is the UTF-8 encoding of the byte order mark U+FEFF.
+const utf8BOM = "\xef\xbb\xbf"
+
 var errInvalidDelim = errors.New("csv: invalid field or comment delimiter")
 
+var errNegativeMaxFieldSize = errors.New("csv: negative MaxFieldSize")
+
 func validDelim(r rune) bool {
-	return r != 0 && r != '"' && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
+	return r != 0 && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
 }
 
 // A Reader reads records from a CSV-encoded file.
@@ -123,6 +157,14 @@ type Reader struct {
 	// It must also not be equal to Comma.
 	Comment rune
 
+	// Quote is the character enclosing quoted fields.
+	// It is set to '"' by NewReader. LazyQuotes, DisableDoubleQuoteEscape and
+	// the errors ErrBareQuote and ErrQuote all refer to this character.
+	// Quote must be a valid rune and must not be \r, \n,
+	// or the Unicode replacement character (0xFFFD).
+	// It must also not be equal to Comma or Comment.
+	Quote rune
+
 	// FieldsPerRecord is the number of expected fields per record.
 	// If FieldsPerRecord is positive, Read requires each record to
 	// have the given number of fields. If FieldsPerRecord is 0, Read sets it to
@@ -139,18 +181,163 @@ type Reader struct {
 	// This is done even if the field delimiter, Comma, is white space.
 	TrimLeadingSpace bool
 
+	// If TrimTrailingSpace is true, trailing white space in a non-quoted
+	// field is ignored. Quoted fields are not affected.
+	TrimTrailingSpace bool
+
+	// If MergeConsecutiveDelimiters is true, a run of several Comma
+	// characters separates two fields like a single one, as in files
+	// aligning columns with repeated delimiters. Empty quoted fields are
+	// still read, and a delimiter at the start of a line still yields an
+	// empty first field. This is not allowed by RFC 4180.
+	MergeConsecutiveDelimiters bool
+
+	// If AllowBareNewlines is true and FieldsPerRecord is positive, a line
+	// break in an unquoted field is read as part of the field, as written by
+	// systems that do not quote such fields. While a record has fewer than
+	// FieldsPerRecord fields, its last field continues on the next line.
+	// The final field of a record continues on each following line that
+	// contains no Comma and is neither blank nor a comment. A line break
+	// in such a field reads as \n. AllowBareNewlines is ignored if
+	// FieldsPerRecord is not positive. This is not allowed by RFC 4180.
+	AllowBareNewlines bool
+
+	// If NormalizeCRLF is true, a bare \r inside a quoted field is read as
+	// \n, like the \r\n sequences which are always read as \n. Delimiters
+	// and line breaks ending records are not affected.
+	NormalizeCRLF bool
+
+	// EscapeChar, if not 0, is an escape character for quoted fields.
+	// Inside a quoted field, EscapeChar followed by any character is read as
+	// that character, so `"a\"b"` reads as a"b with EscapeChar set to '\\'.
+	// EscapeChar must be a valid rune, must not be \r, \n, the quote character
+	// or the Unicode replacement character (0xFFFD), and must not be equal to Comma.
+	EscapeChar rune
+
+	// If DisableDoubleQuoteEscape is true, two consecutive quote characters
+	// inside a quoted field are not read as one literal quote but kept as
+	// two, so "a""b" reads as a""b. Combined with EscapeChar, this reads
+	// formats such as MySQL's OUTFILE that only escape quotes with a
+	// backslash.
+	DisableDoubleQuoteEscape bool
+
+	// MaxFieldSize, if positive, is the maximum length in bytes of a field
+	// value. Read returns a ParseError wrapping ErrFieldTooLarge for longer
+	// fields, which protects against unterminated quoted fields consuming
+	// the whole input. It does not limit the length of lines.
+	MaxFieldSize int
+
 	// ReuseRecord controls whether calls to Read may return a slice sharing
 	// the backing array of the previous call's returned slice for performance.
 	// By default, each call to Read returns newly allocated memory owned by the caller.
 	ReuseRecord bool
 
+	// RecordPrealloc, if non-nil, is called by Read to obtain the slice the
+	// next record is stored in, instead of allocating a new one. It receives
+	// the record returned by the previous call to Read (nil on the first
+	// call), so that it can be recycled, for example into a pool.
+	// The returned slice is used if its capacity suffices for the record.
+	// RecordPrealloc is not called if ReuseRecord is true.
+	RecordPrealloc func(prevRecord []Column) []Column
+
+	// If StripBOM is true, a UTF-8 byte order mark at the start of the
+	// input is discarded. It still counts towards byte offsets.
+	StripBOM bool
+
+	// If SanitizeFields is true, Column.Sanitize is applied to every field
+	// before it is returned, after Unescape.
+	SanitizeFields bool
+
+	// NullValue, if non-nil, is the representation of SQL NULL.
+	// A non-quoted field equal to *NullValue is returned with IsNull set
+	// and an empty Value, so that it can be told apart from the quoted
+	// field "" when NullValue is empty.
+	NullValue *string
+
+	// Unescape, if non-nil, is called on the value of every field before it
+	// is returned. For quoted fields, it receives the content with doubled
+	// quotes already collapsed. It can be used to decode escape sequences
+	// that are not part of RFC 4180, such as those of MySQL's OUTFILE format.
+	Unescape func(value string) string
+
+	// If IgnorePanic is true, a panic inside a hook function such as
+	// Unescape, a transform added by AddTransformer or an error handler set
+	// with SetErrorHandler is recovered and returned as a ParseError wrapping
+	// ErrHookPanic instead of crashing the calling goroutine.
+	IgnorePanic bool
+
+	// RecordChannelSize is the buffer size of the channel returned by
+	// RecordChannel. If it is not positive, a size of 64 is used.
+	RecordChannelSize int
+
+	// If CaptureRaw is true, each Column returned by the Reader has its
+	// OriginalBytes set to the field as it appeared in the input,
+	// including any quotes and escape sequences.
+	CaptureRaw bool
+
+	// If CollectComments is true, the comment lines skipped by the Reader
+	// are appended to Comments as they are read, without the Comment
+	// character and the line terminator. Comments after the last record
+	// are collected by the call returning io.EOF.
+	CollectComments bool
+	Comments        []string
+
+	// If AllowDuplicateHeaders is true, NewHeaderReader accepts a header
+	// row holding the same column name more than once. The second and
+	// later columns with a repeated name are renamed by appending "_2",
+	// "_3", and so on, skipping names already used by other columns.
+	AllowDuplicateHeaders bool
+
+	// SourceName, if set, names the input, such as a file name. It is
+	// reported in the Records returned by ReadRecord and ReadAllRecords.
+	SourceName string
+
+	// HeaderDetectionThreshold is the share of votes for a header above
+	// which DetectHasHeader reports one. If it is not positive, 0.5 is used.
+	HeaderDetectionThreshold float64
+
 	TrailingComma bool // Deprecated: No longer used.
 
 	r *bufio.Reader
 
+	// closer, if non-nil, is closed by Close.
+	closer io.Closer
+
+	// ctx, if non-nil, is the context of the ongoing call to ReadContext.
+	ctx context.Context
+
+	// err is the error that stopped the goroutine started by RecordChannel.
+	err error
+
+	// fieldNames, if non-nil, are the names of the fields, as given by
+	// a header row or schema. They are used to fill in ParseError.FieldName.
+	fieldNames []string
+
+	// peeked holds the records read ahead by Peek.
+	peeked []peekedRecord
+
+	// schema is the schema attached by SetSchema. If schemaHeader is true,
+	// the next record is checked against schema.ColumnNames.
+	schema       *Schema
+	schemaHeader bool
+
+	// bomChecked is set once the input has been checked for a byte
+	// order mark.
+	bomChecked bool
+
 	// numLine is the current line being read in the CSV file.
 	numLine int
 
+	// recLine and endLine are the lines where the last record read starts
+	// and ends.
+	recLine int
+	endLine int
+
+	// offset is the number of bytes consumed from the input, and
+	// lineOffset the offset of the start of the current line.
+	offset     int64
+	lineOffset int64
+
 	// rawBuffer is a line buffer only used by the readLine method.
 	rawBuffer []byte
 
@@ -164,18 +351,169 @@ type Reader struct {
 	// The i'th field ends at offset fieldIndexes[i] in recordBuffer.
 	fieldIndexes []int
 
-	// lastRecord is a record cache and only used when ReuseRecord == true.
+	// positions holds the byte offsets of the fields of the last record.
+	positions []ColumnPosition
+
+	// errorHandler is the function set by SetErrorHandler.
+	errorHandler func(*ParseError, []Column)
+
+	// transformers are the column transforms added by AddTransformer.
+	transformers []columnTransformer
+
+	// rawRecord holds the lines of the record being parsed when CaptureRaw
+	// is set, and rawLines maps their input offsets into rawRecord.
+	rawRecord []byte
+	rawLines  []rawLine
+
+	// held is the line read ahead by peekLine, if any.
+	held *heldLine
+
+	// pool is the ColumnPool set by SetPool.
+	pool *ColumnPool
+
+	// lastRecord is the record returned by the previous call to Read.
+	// It is only kept when ReuseRecord is true or RecordPrealloc is set.
 	lastRecord []Column
+
+	// pending collects the counters of the record being read.
+	// They are added to metrics once the record is complete.
+	pending ReadMetrics
+
+	// mu guards metrics.
+	mu      sync.Mutex
+	metrics ReadMetrics
 }
 
 // NewReader returns a new Reader that reads from r.
 func NewReader(r io.Reader) *Reader {
 	return &Reader{
 		Comma: ',',
+		Quote: '"',
 		r:     bufio.NewReader(r),
 	}
 }
 
+// Clone returns a new Reader reading from rd with the same settings as r,
+// including the schema, error handler, pool and transforms attached to it.
+// The state of r, such as buffered input, records read ahead by Peek,
+// line numbers and metrics, is not copied, nor is SourceName, which
+// names the input of r. FieldsPerRecord is copied as
+// it is, so a clone of a Reader that has set it from the first record
+// expects the same number of fields.
+func (r *Reader) Clone(rd io.Reader) *Reader {
+	c := NewReader(rd)
+	c.Comma = r.Comma
+	c.Comment = r.Comment
+	c.Quote = r.Quote
+	c.FieldsPerRecord = r.FieldsPerRecord
+	c.LazyQuotes = r.LazyQuotes
+	c.TrimLeadingSpace = r.TrimLeadingSpace
+	c.TrimTrailingSpace = r.TrimTrailingSpace
+	c.MergeConsecutiveDelimiters = r.MergeConsecutiveDelimiters
+	c.AllowBareNewlines = r.AllowBareNewlines
+	c.NormalizeCRLF = r.NormalizeCRLF
+	c.EscapeChar = r.EscapeChar
+	c.DisableDoubleQuoteEscape = r.DisableDoubleQuoteEscape
+	c.MaxFieldSize = r.MaxFieldSize
+	c.ReuseRecord = r.ReuseRecord
+	c.RecordPrealloc = r.RecordPrealloc
+	c.StripBOM = r.StripBOM
+	c.SanitizeFields = r.SanitizeFields
+	c.NullValue = r.NullValue
+	c.Unescape = r.Unescape
+	c.IgnorePanic = r.IgnorePanic
+	c.RecordChannelSize = r.RecordChannelSize
+	c.CaptureRaw = r.CaptureRaw
+	c.CollectComments = r.CollectComments
+	c.AllowDuplicateHeaders = r.AllowDuplicateHeaders
+	c.HeaderDetectionThreshold = r.HeaderDetectionThreshold
+	c.TrailingComma = r.TrailingComma
+	c.SetSchema(r.schema)
+	c.errorHandler = r.errorHandler
+	c.transformers = append([]columnTransformer(nil), r.transformers...)
+	c.pool = r.pool
+	return c
+}
+
+// NewFSReader opens the named file from fsys and returns a new Reader
+// that reads from it. The caller should call Close when done to release
+// the underlying file.
+func NewFSReader(fsys fs.FS, name string) (*Reader, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	r := NewReader(f)
+	r.closer = f
+	return r, nil
+}
+
+// Close closes the file opened by NewFSReader.
+// For Readers created by NewReader, Close does nothing and returns nil.
+func (r *Reader) Close() error {
+	if r.closer == nil {
+		return nil
+	}
+	err := r.closer.Close()
+	r.closer = nil
+	return err
+}
+
+// rawLine locates a line of the input within Reader.rawRecord.
+type rawLine struct {
+	offset int64 // input offset of the start of the line
+	base   int   // index of the start of the line in rawRecord
+}
+
+// A heldLine is a line read ahead by Reader.peekLine, together with the
+// reader state after reading it.
+type heldLine struct {
+	line       []byte
+	err        error
+	numLine    int
+	offset     int64
+	lineOffset int64
+	bytesRead  int64
+}
+
+// A peekedRecord is the result of reading a record ahead with Peek.
+type peekedRecord struct {
+	record    []Column
+	err       error
+	positions []ColumnPosition
+	recLine   int
+	endLine   int
+}
+
+// A ColumnPosition holds the location of a field in the input.
+// StartOffset is the byte offset of the first byte of the field, including
+// any opening quote or leading white space. EndOffset is the offset just past
+// the last byte of the field, which is where the following delimiter or line
+// terminator starts.
+type ColumnPosition struct {
+	StartOffset int64
+	EndOffset   int64
+}
+
+// Positions returns the locations in the input of the fields of the record
+// most recently returned by Read, one per field.
+// The returned slice is only valid until the next call to Read.
+func (r *Reader) Positions() []ColumnPosition {
+	return r.positions
+}
+
+// InputOffset returns the input stream byte offset of the current reader
+// position. The offset gives the location of the end of the most recently
+// read row and the beginning of the next row. After a ParseError, it is
+// the end of the line on which the error was found. It counts a byte
+// order mark removed by StripBOM.
+//
+// Records read ahead by Peek are included, so after a call to Peek the
+// offset is past the last record Peek returned.
+func (r *Reader) InputOffset() int64 {
+	return r.offset
+}
+
 // Read reads one record (a slice of fields) from r.
 // If the record has an unexpected number of fields,
 // Read returns the record along with the error ErrFieldCount.
@@ -188,12 +526,155 @@ func (r *Reader) Read() (record []Column, err error) {
 	if r.ReuseRecord {
 		record, err = r.readRecord(r.lastRecord)
 		r.lastRecord = record
+	} else if r.RecordPrealloc != nil {
+		record, err = r.readRecord(r.RecordPrealloc(r.lastRecord))
+		r.lastRecord = record
+	} else if r.pool != nil {
+		dst := r.pool.Get()
+		record, err = r.readRecord(dst)
+		if record == nil {
+			r.pool.Put(dst)
+		}
 	} else {
 		record, err = r.readRecord(nil)
 	}
 	return record, err
 }
 
+// ReadContext is like Read but stops once ctx is done. The context is
+// checked before reading each line from the underlying io.Reader, so a
+// record spanning several lines is abandoned as soon as ctx is done.
+// In that case ReadContext returns nil and ctx.Err(), and the part of
+// the record read so far is lost. A read that is blocked on the
+// underlying io.Reader is not interrupted.
+func (r *Reader) ReadContext(ctx context.Context) (record []Column, err error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r.ctx = ctx
+	record, err = r.Read()
+	r.ctx = nil
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			return nil, ctxErr
+		}
+	}
+	return record, err
+}
+
+// RecordChannel starts a goroutine that reads the remaining records from r
+// and sends them on the returned channel, which has a buffer of
+// RecordChannelSize records. The channel is closed when the input is
+// exhausted, when Read returns an error, or when ctx is done. After the
+// channel is closed, Err reports why.
+// Records sent on the channel are never shared, even if ReuseRecord is
+// true or RecordPrealloc is set.
+// r must not be used by other goroutines until the channel is closed.
+func (r *Reader) RecordChannel(ctx context.Context) <-chan []Column {
+	size := r.RecordChannelSize
+	if size <= 0 {
+		size = 64
+	}
+	ch := make(chan []Column, size)
+	r.err = nil
+	go func() {
+		defer close(ch)
+		for {
+			record, err := r.ReadContext(ctx)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				r.err = err
+				return
+			}
+			if r.ReuseRecord || r.RecordPrealloc != nil {
+				record = append([]Column(nil), record...)
+			}
+			select {
+			case ch <- record:
+			case <-ctx.Done():
+				r.err = ctx.Err()
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Err returns the error that stopped the goroutine started by
+// RecordChannel, or nil if it read all records. It must only be called
+// after the channel returned by RecordChannel has been closed.
+func (r *Reader) Err() error {
+	return r.err
+}
+
+// SkipRows reads and discards the next n records, such as metadata rows
+// preceding the header row, and returns the number of records skipped.
+// Comment lines and blank lines are skipped as usual and are not counted.
+// Skipped records are read as if LazyQuotes were set, so that free-form
+// text in them does not cause errors; they are not checked against
+// FieldsPerRecord or a schema and do not set FieldsPerRecord.
+// Line numbers reported afterwards still count the skipped lines.
+// If the input ends before n records are skipped, SkipRows returns io.EOF.
+func (r *Reader) SkipRows(n int) (int, error) {
+	lazy, fields := r.LazyQuotes, r.FieldsPerRecord
+	r.LazyQuotes, r.FieldsPerRecord = true, -1
+	defer func() { r.LazyQuotes, r.FieldsPerRecord = lazy, fields }()
+
+	for i := 0; i < n; i++ {
+		if len(r.peeked) > 0 {
+			r.peeked[0] = peekedRecord{}
+			r.peeked = r.peeked[1:]
+			continue
+		}
+		_, err := r.parseRecord(nil)
+		r.updateMetrics(nil, err)
+		if err != nil {
+			return i, err
+		}
+	}
+	return n, nil
+}
+
+// Peek returns the next n records without consuming them: they are
+// returned again, in order, by the following calls to Read and the other
+// reading methods. Fewer than n records are returned if the input ends
+// first. If reading a record fails, Peek returns the records before it
+// along with the error, which is returned again by Read when it reaches
+// that record. Line numbers in errors refer to the input as usual.
+//
+// Records returned by Peek are never shared, even if ReuseRecord is true.
+func (r *Reader) Peek(n int) ([][]Column, error) {
+	for len(r.peeked) < n {
+		if last := len(r.peeked) - 1; last >= 0 && r.peeked[last].err != nil {
+			break
+		}
+		record, err := r.nextRecord(nil)
+		if err == io.EOF {
+			break
+		}
+		r.peeked = append(r.peeked, peekedRecord{
+			record:    record,
+			err:       err,
+			positions: append([]ColumnPosition(nil), r.positions...),
+			recLine:   r.recLine,
+			endLine:   r.endLine,
+		})
+	}
+	var records [][]Column
+	for i, p := range r.peeked {
+		if i == n {
+			break
+		}
+		if p.err != nil {
+			return records, p.err
+		}
+		records = append(records, p.record)
+	}
+	return records, nil
+}
+
 // ReadAll reads all the remaining records from r.
 // Each record is a slice of fields.
 // A successful call returns err == nil, not err == io.EOF. Because ReadAll is
@@ -212,11 +693,176 @@ func (r *Reader) ReadAll() (records [][]Column, err error) {
 	}
 }
 
+// ReadStrings is like Read but returns the values of the fields only.
+// Null fields read as empty strings. The returned slice is newly
+// allocated even if ReuseRecord is true.
+func (r *Reader) ReadStrings() ([]string, error) {
+	record, err := r.Read()
+	if record == nil {
+		return nil, err
+	}
+	return columnValues(record), err
+}
+
+// ReadAllStrings is like ReadAll but returns the values of the fields
+// only. Null fields read as empty strings.
+func (r *Reader) ReadAllStrings() (records [][]string, err error) {
+	var scratch []Column // Reused for parsing, as only the values are kept
+	for {
+		peeked := len(r.peeked) > 0
+		record, err := r.readRecord(scratch)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, columnValues(record))
+		if !peeked {
+			// Records returned by Peek are never shared.
+			scratch = record
+		}
+	}
+}
+
+// columnValues returns the values of record.
+func columnValues(record []Column) []string {
+	values := make([]string, len(record))
+	for i, col := range record {
+		values[i] = col.Value
+	}
+	return values
+}
+
+// ReadN reads up to n of the remaining records from r, for processing
+// a large input in pages. If fewer than n records remain, ReadN returns
+// them with a nil error; once the input is exhausted it returns nil, io.EOF.
+// Each returned record is a separate slice, even if ReuseRecord or
+// RecordPrealloc is set.
+// If an error occurs, ReadN returns the records read before it along with
+// the error.
+func (r *Reader) ReadN(n int) (records [][]Column, err error) {
+	for len(records) < n {
+		record, err := r.readRecord(nil)
+		if err == io.EOF {
+			if len(records) == 0 {
+				return nil, io.EOF
+			}
+			return records, nil
+		}
+		if err != nil {
+			return records, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// ReadSingleColumn reads all the remaining records from r, which must
+// each have exactly one field, and returns their values. If a record has
+// a different number of fields, ReadSingleColumn returns a ParseError
+// wrapping ErrFieldCount. Like ReadAll, it does not report io.EOF.
+func (r *Reader) ReadSingleColumn() ([]string, error) {
+	var values []string
+	for {
+		record, err := r.readRecord(nil)
+		if err == io.EOF {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(record) != 1 {
+			return nil, &ParseError{StartLine: r.recLine, Line: r.recLine, Err: ErrFieldCount}
+		}
+		values = append(values, record[0].Value)
+	}
+}
+
+// ForEach reads the remaining records from r and calls fn for each of them.
+// Records are subject to ReuseRecord and RecordPrealloc as with Read,
+// so fn must not retain the slice it is given if either is set.
+//
+// If fn returns a non-nil error, ForEach stops and returns that error
+// unchanged. Otherwise ForEach returns the first error returned by Read,
+// or nil once the input is exhausted.
+func (r *Reader) ForEach(fn func(record []Column) error) error {
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadAllFiltered is like ReadAll but only keeps the records for which
+// keep returns true.
+func (r *Reader) ReadAllFiltered(keep func(record []Column) bool) (records [][]Column, err error) {
+	for {
+		record, err := r.readRecord(nil)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if keep(record) {
+			records = append(records, record)
+		}
+	}
+}
+
+// captureLine appends line, which starts at r.lineOffset, to the raw bytes
+// of the current record if CaptureRaw is set.
+func (r *Reader) captureLine(line []byte) {
+	if !r.CaptureRaw || line == nil {
+		return
+	}
+	r.rawLines = append(r.rawLines, rawLine{r.lineOffset, len(r.rawRecord)})
+	r.rawRecord = append(r.rawRecord, line...)
+}
+
+// rawIndex converts the input offset off within the current record
+// to an index into r.rawRecord.
+func (r *Reader) rawIndex(off int64) int {
+	for i := len(r.rawLines) - 1; i >= 0; i-- {
+		if l := r.rawLines[i]; l.offset <= off {
+			return l.base + int(off-l.offset)
+		}
+	}
+	return 0
+}
+
 // readLine reads the next line (with the trailing endline).
 // If EOF is hit without a trailing endline, it will be omitted.
 // If some bytes were read, then the error is never io.EOF.
 // The result is only valid until the next call to readLine.
 func (r *Reader) readLine() ([]byte, error) {
+	if h := r.held; h != nil {
+		r.held = nil
+		r.numLine, r.offset, r.lineOffset = h.numLine, h.offset, h.lineOffset
+		r.pending.BytesRead += h.bytesRead
+		return h.line, h.err
+	}
+	if r.ctx != nil {
+		if err := r.ctx.Err(); err != nil {
+			return nil, err
+		}
+	}
+	if r.StripBOM && !r.bomChecked {
+		r.bomChecked = true
+		if b, _ := r.r.Peek(len(utf8BOM)); string(b) == utf8BOM {
+			r.r.Discard(len(utf8BOM))
+			r.pending.BytesRead += int64(len(utf8BOM))
+			r.offset += int64(len(utf8BOM))
+		}
+	}
 	line, err := r.r.ReadSlice('\n')
 	if err == bufio.ErrBufferFull {
 		r.rawBuffer = append(r.rawBuffer[:0], line...)
@@ -226,6 +872,9 @@ func (r *Reader) readLine() ([]byte, error) {
 		}
 		line = r.rawBuffer
 	}
+	r.pending.BytesRead += int64(len(line))
+	r.lineOffset = r.offset
+	r.offset += int64(len(line))
 	if len(line) > 0 && err == io.EOF {
 		err = nil
 		// For backwards compatibility, drop trailing \r before EOF.
@@ -242,6 +891,59 @@ func (r *Reader) readLine() ([]byte, error) {
 	return line, err
 }
 
+// peekLine returns the next line without consuming it.
+// The following call to readLine returns the same line.
+func (r *Reader) peekLine() ([]byte, error) {
+	if r.held == nil {
+		numLine, offset, lineOffset, bytesRead := r.numLine, r.offset, r.lineOffset, r.pending.BytesRead
+		line, err := r.readLine()
+		r.held = &heldLine{
+			line:       append([]byte(nil), line...),
+			err:        err,
+			numLine:    r.numLine,
+			offset:     r.offset,
+			lineOffset: r.lineOffset,
+			bytesRead:  r.pending.BytesRead - bytesRead,
+		}
+		r.numLine, r.offset, r.lineOffset, r.pending.BytesRead = numLine, offset, lineOffset, bytesRead
+	}
+	return r.held.line, r.held.err
+}
+
+// bareNewline reports whether the unquoted field ending at the end of
+// line continues on the next line because of AllowBareNewlines.
+// nfields is the number of fields of the record before this one.
+func (r *Reader) bareNewline(line []byte, nfields int) bool {
+	if !r.AllowBareNewlines || r.FieldsPerRecord <= 0 || lengthNL(line) == 0 {
+		return false
+	}
+	next, err := r.peekLine()
+	if err != nil {
+		return false
+	}
+	if nfields+1 < r.FieldsPerRecord {
+		return true
+	}
+	if nfields+1 > r.FieldsPerRecord || len(next) == lengthNL(next) {
+		return false
+	}
+	if r.Comment != 0 && nextRune(next) == r.Comment {
+		return false
+	}
+	return bytes.IndexRune(next, r.Comma) < 0
+}
+
+// skipDelimiters returns line without any leading Comma characters
+// if MergeConsecutiveDelimiters is set.
+func (r *Reader) skipDelimiters(line []byte) []byte {
+	if r.MergeConsecutiveDelimiters {
+		for nextRune(line) == r.Comma && len(line) > 0 {
+			line = line[utf8.RuneLen(r.Comma):]
+		}
+	}
+	return line
+}
+
 // lengthNL reports the number of bytes for the trailing \n.
 func lengthNL(b []byte) int {
 	if len(b) > 0 && b[len(b)-1] == '\n' {
@@ -256,9 +958,85 @@ func nextRune(b []byte) rune {
 	return r
 }
 
+// readRecord reads one record and updates the Reader's metrics.
+// readRecord returns the next record, taking it from the records buffered
+// by Peek if there are any.
 func (r *Reader) readRecord(dst []Column) ([]Column, error) {
+	if len(r.peeked) > 0 {
+		p := r.peeked[0]
+		r.peeked[0] = peekedRecord{}
+		r.peeked = r.peeked[1:]
+		r.positions = append(r.positions[:0], p.positions...)
+		r.recLine, r.endLine = p.recLine, p.endLine
+		if p.record != nil && (r.ReuseRecord || r.RecordPrealloc != nil) {
+			// Keep the slice returned by Peek from being recycled.
+			p.record = append(dst[:0], p.record...)
+		}
+		return p.record, p.err
+	}
+	return r.nextRecord(dst)
+}
+
+// nextRecord parses and validates the next record of the input.
+// Records failing with a ParseError are passed to the error handler,
+// if one is set, and skipped.
+func (r *Reader) nextRecord(dst []Column) ([]Column, error) {
+	for {
+		record, err := r.parseRecord(dst)
+		perr, isParseErr := err.(*ParseError)
+		if isParseErr && r.fieldNames != nil {
+			switch perr.Err {
+			case ErrBareQuote, ErrQuote, ErrFieldTooLarge:
+				// The field in error is the first one not yet complete.
+				if i := len(r.fieldIndexes); i < len(r.fieldNames) {
+					perr.FieldName = r.fieldNames[i]
+				}
+			}
+		}
+		if err == nil && r.schema != nil {
+			err = r.validate(record, r.recLine)
+		}
+		r.updateMetrics(record, err)
+		if isParseErr && r.errorHandler != nil {
+			if errHook := r.callHook(perr.StartLine, func() { r.errorHandler(perr, record) }); errHook != nil {
+				return nil, errHook
+			}
+			continue
+		}
+		return record, err
+	}
+}
+
+// SetErrorHandler sets fn to be called with every ParseError encountered
+// by r, including those wrapping ErrFieldCount, instead of returning it
+// from Read. The record in error is then skipped and Read continues with
+// the next one. fn receives the fields parsed before the error, or the
+// whole record for ErrFieldCount; they are only valid during the call.
+// Passing a nil fn restores the default of returning parse errors.
+func (r *Reader) SetErrorHandler(fn func(err *ParseError, record []Column)) {
+	r.errorHandler = fn
+}
+
+// checkConfig returns an error if the settings of r are invalid.
+func (r *Reader) checkConfig() error {
 	if r.Comma == r.Comment || !validDelim(r.Comma) || (r.Comment != 0 && !validDelim(r.Comment)) {
-		return nil, errInvalidDelim
+		return errInvalidDelim
+	}
+	if !validQuote(r.Quote) || r.Quote == r.Comma || r.Quote == r.Comment {
+		return errInvalidDelim
+	}
+	if r.EscapeChar != 0 && (r.EscapeChar == r.Comma || r.EscapeChar == r.Quote || !validDelim(r.EscapeChar)) {
+		return errInvalidDelim
+	}
+	if r.MaxFieldSize < 0 {
+		return errNegativeMaxFieldSize
+	}
+	return nil
+}
+
+func (r *Reader) parseRecord(dst []Column) ([]Column, error) {
+	if err := r.checkConfig(); err != nil {
+		return nil, err
 	}
 
 	// Read line (automatically skipping past empty lines and any comments).
@@ -267,54 +1045,99 @@ func (r *Reader) readRecord(dst []Column) ([]Column, error) {
 	for errRead == nil {
 		line, errRead = r.readLine()
 		if r.Comment != 0 && nextRune(line) == r.Comment {
+			if r.CollectComments {
+				text := line[utf8.RuneLen(r.Comment):]
+				r.Comments = append(r.Comments, string(text[:len(text)-lengthNL(text)]))
+			}
 			line = nil
+			r.pending.CommentLinesSkipped++
 			continue // Skip comment lines
 		}
 		if errRead == nil && len(line) == lengthNL(line) {
 			line = nil
+			r.pending.BlankLinesSkipped++
 			continue // Skip empty lines
 		}
 		fullLine = line
 		break
 	}
+	r.rawRecord = r.rawRecord[:0]
+	r.rawLines = r.rawLines[:0]
+	r.captureLine(fullLine)
 	if errRead == io.EOF {
 		return nil, errRead
 	}
 
 	// Parse each field in the record.
 	var err error
-	const quoteLen = len(`"`)
+	quote := string(r.Quote)
+	quoteLen := len(quote)
 	const quoteBit = 0x8000_0000
 	commaLen := utf8.RuneLen(r.Comma)
+	var specials string // Characters ending a run of quoted field content.
+	if r.EscapeChar != 0 {
+		specials = string([]rune{r.Quote, r.EscapeChar})
+	}
 	recLine := r.numLine // Starting line for record
+	r.recLine = recLine
 	r.recordBuffer = r.recordBuffer[:0]
 	r.fieldIndexes = r.fieldIndexes[:0]
+	r.positions = r.positions[:0]
 parseField:
 	for {
+		// Offset of the first byte of the field.
+		start := r.lineOffset + int64(len(fullLine)-len(line))
+		fieldStart := len(r.recordBuffer)
 		if r.TrimLeadingSpace {
 			line = bytes.TrimLeftFunc(line, unicode.IsSpace)
 		}
-		if len(line) == 0 || line[0] != '"' {
+		if len(line) == 0 || nextRune(line) != r.Quote {
 			// Non-quoted string field
-			i := bytes.IndexRune(line, r.Comma)
-			field := line
-			if i >= 0 {
-				field = field[:i]
-			} else {
-				field = field[:len(field)-lengthNL(field)]
-			}
-			// Check to make sure a quote does not appear in field.
-			if !r.LazyQuotes {
-				if j := bytes.IndexByte(field, '"'); j >= 0 {
-					col := utf8.RuneCount(fullLine[:len(fullLine)-len(line[j:])])
-					err = &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrBareQuote}
+			var i int
+			var field []byte
+			for {
+				i = bytes.IndexRune(line, r.Comma)
+				field = line
+				if i >= 0 {
+					field = field[:i]
+				} else {
+					field = field[:len(field)-lengthNL(field)]
+				}
+				// Check to make sure a quote does not appear in field.
+				if !r.LazyQuotes {
+					if j := bytes.IndexRune(field, r.Quote); j >= 0 {
+						col := utf8.RuneCount(fullLine[:len(fullLine)-len(line[j:])])
+						err = &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrBareQuote}
+						break parseField
+					}
+				}
+				seg, prevLen := len(fullLine)-len(line), len(r.recordBuffer)
+				if i >= 0 || !r.bareNewline(line, len(r.fieldIndexes)) {
+					value := field
+					if r.TrimTrailingSpace {
+						value = bytes.TrimRightFunc(value, unicode.IsSpace)
+					}
+					r.recordBuffer = append(r.recordBuffer, value...)
+					if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
+						break parseField
+					}
+					break
+				}
+				// Bare line break (continue the field on the next line).
+				r.recordBuffer = append(r.recordBuffer, field...)
+				r.recordBuffer = append(r.recordBuffer, '\n')
+				if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
 					break parseField
 				}
+				line, errRead = r.readLine()
+				fullLine = line
+				r.captureLine(fullLine)
 			}
-			r.recordBuffer = append(r.recordBuffer, field...)
 			r.fieldIndexes = append(r.fieldIndexes, len(r.recordBuffer))
+			end := r.lineOffset + int64(len(fullLine)-len(line)+len(field))
+			r.positions = append(r.positions, ColumnPosition{start, end})
 			if i >= 0 {
-				line = line[i+commaLen:]
+				line = r.skipDelimiters(line[i+commaLen:])
 				continue parseField
 			}
 			break parseField
@@ -322,37 +1145,78 @@ parseField:
 			// Quoted string field
 			line = line[quoteLen:]
 			for {
-				i := bytes.IndexByte(line, '"')
-				if i >= 0 {
+				i := bytes.IndexRune(line, r.Quote)
+				if r.EscapeChar != 0 {
+					i = bytes.IndexAny(line, specials)
+				}
+				if i >= 0 && nextRune(line[i:]) != r.Quote {
+					// Hit escape character (append next rune verbatim).
+					seg, prevLen := len(fullLine)-len(line), len(r.recordBuffer)
+					r.recordBuffer = append(r.recordBuffer, line[:i]...)
+					line = line[i+utf8.RuneLen(r.EscapeChar):]
+					_, n := utf8.DecodeRune(line)
+					r.recordBuffer = append(r.recordBuffer, line[:n]...)
+					line = line[n:]
+					if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
+						break parseField
+					}
+					if len(line) == 0 && errRead == nil {
+						// Escaped end of line (continue on the next line).
+						line, errRead = r.readLine()
+						if errRead == io.EOF {
+							errRead = nil
+						}
+						fullLine = line
+						r.captureLine(fullLine)
+					}
+				} else if i >= 0 {
 					// Hit next quote.
+					seg, prevLen := len(fullLine)-len(line), len(r.recordBuffer)
 					r.recordBuffer = append(r.recordBuffer, line[:i]...)
+					if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
+						break parseField
+					}
 					line = line[i+quoteLen:]
 					switch rn := nextRune(line); {
-					case rn == '"':
+					case rn == r.Quote && !r.DisableDoubleQuoteEscape:
 						// `""` sequence (append quote).
-						r.recordBuffer = append(r.recordBuffer, '"')
+						r.recordBuffer = append(r.recordBuffer, quote...)
+						line = line[quoteLen:]
+					case rn == r.Quote:
+						// `""` sequence with DisableDoubleQuoteEscape (append both quotes).
+						r.recordBuffer = append(r.recordBuffer, quote...)
+						r.recordBuffer = append(r.recordBuffer, quote...)
 						line = line[quoteLen:]
 					case rn == r.Comma:
 						// `",` sequence (end of field).
-						line = line[commaLen:]
+						r.positions = append(r.positions, ColumnPosition{start, r.lineOffset + int64(len(fullLine)-len(line))})
+						line = r.skipDelimiters(line[commaLen:])
 						r.fieldIndexes = append(r.fieldIndexes, len(r.recordBuffer)|quoteBit)
 						continue parseField
 					case lengthNL(line) == len(line):
 						// `"\n` sequence (end of line).
+						r.positions = append(r.positions, ColumnPosition{start, r.lineOffset + int64(len(fullLine)-len(line))})
 						r.fieldIndexes = append(r.fieldIndexes, len(r.recordBuffer)|quoteBit)
 						break parseField
 					case r.LazyQuotes:
 						// `"` sequence (bare quote).
-						r.recordBuffer = append(r.recordBuffer, '"')
+						r.recordBuffer = append(r.recordBuffer, quote...)
 					default:
 						// `"*` sequence (invalid non-escaped quote).
 						col := utf8.RuneCount(fullLine[:len(fullLine)-len(line)-quoteLen])
 						err = &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrQuote}
 						break parseField
 					}
+					if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
+						break parseField
+					}
 				} else if len(line) > 0 {
 					// Hit end of line (copy all data so far).
+					seg, prevLen := len(fullLine)-len(line), len(r.recordBuffer)
 					r.recordBuffer = append(r.recordBuffer, line...)
+					if err = r.checkFieldSize(fullLine, seg, prevLen, fieldStart, recLine); err != nil {
+						break parseField
+					}
 					if errRead != nil {
 						break parseField
 					}
@@ -361,6 +1225,7 @@ parseField:
 						errRead = nil
 					}
 					fullLine = line
+					r.captureLine(fullLine)
 				} else {
 					// Abrupt end of file (EOF or error).
 					if !r.LazyQuotes && errRead == nil {
@@ -368,16 +1233,33 @@ parseField:
 						err = &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrQuote}
 						break parseField
 					}
+					r.positions = append(r.positions, ColumnPosition{start, r.lineOffset + int64(len(fullLine))})
 					r.fieldIndexes = append(r.fieldIndexes, len(r.recordBuffer))
 					break parseField
 				}
 			}
 		}
 	}
+	r.endLine = r.numLine
 	if err == nil {
 		err = errRead
 	}
 
+	if r.NormalizeCRLF {
+		var preIdx int
+		for _, idx := range r.fieldIndexes {
+			end := idx &^ quoteBit
+			if idx&quoteBit == quoteBit {
+				for i, b := range r.recordBuffer[preIdx:end] {
+					if b == '\r' {
+						r.recordBuffer[preIdx+i] = '\n'
+					}
+				}
+			}
+			preIdx = end
+		}
+	}
+
 	// Create a single string and create slices out of it.
 	// This pins the memory of the fields together, but allocates once.
 	str := string(r.recordBuffer) // Convert to string once to batch allocations
@@ -394,8 +1276,42 @@ parseField:
 			Quoted: quoted,
 			Value:  str[preIdx:idx],
 		}
+		if !quoted && r.NullValue != nil && dst[i].Value == *r.NullValue {
+			dst[i] = Column{IsNull: true}
+		}
 		preIdx = idx
 	}
+	if r.Unescape != nil {
+		errHook := r.callHook(recLine, func() {
+			for i := range dst {
+				if !dst[i].IsNull {
+					dst[i].Value = r.Unescape(dst[i].Value)
+				}
+			}
+		})
+		if errHook != nil {
+			return nil, errHook
+		}
+	}
+	if r.SanitizeFields {
+		for i := range dst {
+			if !dst[i].IsNull {
+				dst[i] = dst[i].Sanitize()
+			}
+		}
+	}
+	if r.CaptureRaw {
+		raw := string(r.rawRecord) // Allocate once for all fields
+		for i, pos := range r.positions {
+			lo, hi := r.rawIndex(pos.StartOffset), r.rawIndex(pos.EndOffset)
+			dst[i].OriginalBytes = raw[lo:hi]
+		}
+	}
+	if r.transformers != nil {
+		if errHook := r.callHook(recLine, func() { r.transform(dst) }); errHook != nil {
+			return nil, errHook
+		}
+	}
 
 	// Check or update the expected fields per record.
 	if r.FieldsPerRecord > 0 {
@@ -407,3 +1323,34 @@ parseField:
 	}
 	return dst, err
 }
+
+// checkFieldSize returns a ParseError wrapping ErrFieldTooLarge if the
+// field starting at fieldStart in recordBuffer is longer than MaxFieldSize.
+// The bytes appended to recordBuffer since it had length prevLen were taken
+// from fullLine starting at index seg.
+func (r *Reader) checkFieldSize(fullLine []byte, seg, prevLen, fieldStart, recLine int) error {
+	if r.MaxFieldSize <= 0 || len(r.recordBuffer)-fieldStart <= r.MaxFieldSize {
+		return nil
+	}
+	// Index in fullLine of the first byte beyond the limit.
+	i := seg + r.MaxFieldSize - (prevLen - fieldStart)
+	if i > len(fullLine) {
+		i = len(fullLine)
+	}
+	col := utf8.RuneCount(fullLine[:i])
+	return &ParseError{StartLine: recLine, Line: r.numLine, Column: col, Err: ErrFieldTooLarge}
+}
+
+// callHook calls fn. If IgnorePanic is true, a panic inside fn is
+// recovered and returned as a ParseError wrapping ErrHookPanic.
+func (r *Reader) callHook(recLine int, fn func()) (err error) {
+	if r.IgnorePanic {
+		defer func() {
+			if p := recover(); p != nil {
+				err = &ParseError{StartLine: recLine, Line: r.numLine, Err: fmt.Errorf("%w: %v", ErrHookPanic, p)}
+			}
+		}()
+	}
+	fn()
+	return nil
+}
@@ -5,10 +5,16 @@
 package csv
 
 import (
+	"context"
+	"embed"
+	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 	"unicode/utf8"
 )
 
@@ -22,11 +28,21 @@ func TestRead(t *testing.T) {
 		// These fields are copied into the Reader
 		Comma              rune
 		Comment            rune
+		Quote              rune
 		UseFieldsPerRecord bool // false (default) means FieldsPerRecord is -1
 		FieldsPerRecord    int
 		LazyQuotes         bool
 		TrimLeadingSpace   bool
+		TrimTrailingSpace  bool
+		SanitizeFields     bool
 		ReuseRecord        bool
+		Unescape           func(string) string
+		EscapeChar         rune
+		DisableDoubleQuote bool
+		MaxFieldSize       int
+		MergeDelimiters    bool
+		BareNewlines       bool
+		NormalizeCRLF      bool
 	}{{
 		Name:   "Simple",
 		Input:  "a,b,c\n",
@@ -349,6 +365,326 @@ x,,,
 		Input:      `"""""""`,
 		Output:     [][]Column{{c(`"""`)}},
 		LazyQuotes: true,
+	}, {
+		Name:   "EvenQuotesSingle",
+		Input:  `''''''''`,
+		Output: [][]Column{{q(`'''`)}},
+		Quote:  '\'',
+	}, {
+		Name:  "OddQuotesSingle",
+		Input: `'''''''`,
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 7, Err: ErrQuote},
+		Quote: '\'',
+	}, {
+		Name:       "LazyOddQuotesSingle",
+		Input:      `'''''''`,
+		Output:     [][]Column{{c(`'''`)}},
+		LazyQuotes: true,
+		Quote:      '\'',
+	}, {
+		Name:  "ExtraneousQuoteSingle",
+		Input: `'a 'word','b'`,
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 3, Err: ErrQuote},
+		Quote: '\'',
+	}, {
+		Name:  "BadBareQuoteSingle",
+		Input: `a 'word','b'`,
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 2, Err: ErrBareQuote},
+		Quote: '\'',
+	}, {
+		Name:   "DoubleQuoteLiteralSingle",
+		Input:  `'say "hi"',a"b`,
+		Output: [][]Column{{q(`say "hi"`), c(`a"b`)}},
+		Quote:  '\'',
+	}, {
+		Name:   "EvenQuotesBacktick",
+		Input:  "````````",
+		Output: [][]Column{{q("```")}},
+		Quote:  '`',
+	}, {
+		Name:  "OddQuotesBacktick",
+		Input: "```````",
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 7, Err: ErrQuote},
+		Quote: '`',
+	}, {
+		Name:       "LazyOddQuotesBacktick",
+		Input:      "```````",
+		Output:     [][]Column{{c("```")}},
+		LazyQuotes: true,
+		Quote:      '`',
+	}, {
+		Name:  "ExtraneousQuoteBacktick",
+		Input: "`a `word`,`b`",
+		Error: &ParseError{StartLine: 1, Line: 1, Column: 3, Err: ErrQuote},
+		Quote: '`',
+	}, {
+		Name:   "MultiLineBacktick",
+		Input:  "`a\nb`,c\n",
+		Output: [][]Column{{q("a\nb"), c("c")}},
+		Quote:  '`',
+	}, {
+		Name:       "EscapeCharSingle",
+		Input:      `'it\'s',x`,
+		Output:     [][]Column{{q(`it's`), c("x")}},
+		Quote:      '\'',
+		EscapeChar: '\\',
+	}, {
+		Name:       "MultiByteQuote",
+		Input:      "«a«««,b«",
+		Output:     [][]Column{{q("a«"), c("b«")}},
+		Quote:      '«',
+		LazyQuotes: true,
+	}, {
+		Name:            "MergeDelimiters",
+		Input:           "a,,,b\n",
+		Output:          [][]Column{{c("a"), c("b")}},
+		MergeDelimiters: true,
+	}, {
+		Name:            "MergeDelimitersQuotedEmpty",
+		Input:           "a,\"\",b\n",
+		Output:          [][]Column{{c("a"), q(""), c("b")}},
+		MergeDelimiters: true,
+	}, {
+		Name:            "MergeDelimitersAfterQuoted",
+		Input:           "\"a\",,,\"\",,b\n",
+		Output:          [][]Column{{q("a"), q(""), c("b")}},
+		MergeDelimiters: true,
+	}, {
+		Name:            "MergeDelimitersEnds",
+		Input:           ";;a;;;\n",
+		Output:          [][]Column{{c(""), c("a"), c("")}},
+		Comma:           ';',
+		MergeDelimiters: true,
+	}, {
+		Name:               "MergeDelimitersFieldCount",
+		Input:              "a,,b\nc,d\ne,,,f,,g\n",
+		Error:              &ParseError{StartLine: 3, Line: 3, Err: ErrFieldCount},
+		UseFieldsPerRecord: true,
+		MergeDelimiters:    true,
+	}, {
+		Name:               "MergeDelimitersFieldsPerRecord",
+		Input:              "a,,b\nc,d\ne,,,,f\n",
+		Output:             [][]Column{{c("a"), c("b")}, {c("c"), c("d")}, {c("e"), c("f")}},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		MergeDelimiters:    true,
+	}, {
+		Name:               "BareNewlinesLastField",
+		Input:              "1,hello\nworld\n2,foo\n3,a\nb\nc\n",
+		Output:             [][]Column{{c("1"), c("hello\nworld")}, {c("2"), c("foo")}, {c("3"), c("a\nb\nc")}},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		BareNewlines:       true,
+	}, {
+		Name:               "BareNewlinesFirstField",
+		Input:              "a\nb,c\nd,\"e\"\n",
+		Output:             [][]Column{{c("a\nb"), c("c")}, {c("d"), q("e")}},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		BareNewlines:       true,
+	}, {
+		Name:               "BareNewlinesCRLF",
+		Input:              "1,a\r\nb\r\n2,c\r\n",
+		Output:             [][]Column{{c("1"), c("a\nb")}, {c("2"), c("c")}},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		BareNewlines:       true,
+	}, {
+		Name:               "BareNewlinesBlankLine",
+		Input:              "1,a\n\nb\n",
+		Error:              &ParseError{StartLine: 3, Line: 3, Err: ErrFieldCount},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		BareNewlines:       true,
+	}, {
+		Name:               "BareNewlinesFieldCount",
+		Input:              "1,a\nb\n2,3,4\n",
+		Error:              &ParseError{StartLine: 3, Line: 3, Err: ErrFieldCount},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    2,
+		BareNewlines:       true,
+	}, {
+		Name:               "BareNewlinesVariableFields",
+		Input:              "1,hello\nworld\n",
+		Output:             [][]Column{{c("1"), c("hello")}, {c("world")}},
+		UseFieldsPerRecord: true,
+		FieldsPerRecord:    -1,
+		BareNewlines:       true,
+	}, {
+		Name:   "QuotedLineBreaks",
+		Input:  "\"a\rb\",\"c\r\nd\",\"e\nf\",\"g\r\r\nh\",i\rj\r\n",
+		Output: [][]Column{{q("a\rb"), q("c\nd"), q("e\nf"), q("g\r\nh"), c("i\rj")}},
+	}, {
+		Name:          "NormalizeCRLF",
+		Input:         "\"a\rb\",\"c\r\nd\",\"e\nf\",\"g\r\r\nh\",i\rj\r\n",
+		Output:        [][]Column{{q("a\nb"), q("c\nd"), q("e\nf"), q("g\n\nh"), c("i\rj")}},
+		NormalizeCRLF: true,
+	}, {
+		Name:  "BadQuoteComma",
+		Input: "a,b",
+		Error: errInvalidDelim,
+		Quote: ',',
+	}, {
+		Name:  "BadQuoteNewline",
+		Input: "a,b",
+		Error: errInvalidDelim,
+		Quote: '\n',
+	}, {
+		Name:     "UnescapeMySQL",
+		Input:    `a\nb,c\td,e\\f` + "\n",
+		Output:   [][]Column{{c("a\nb"), c("c\td"), c(`e\f`)}},
+		Unescape: mysqlUnescape,
+	}, {
+		Name:     "UnescapeQuoted",
+		Input:    `"a\n""b",plain` + "\n",
+		Output:   [][]Column{{q("a\n\"b"), c("plain")}},
+		Unescape: mysqlUnescape,
+	}, {
+		Name:               "EscapedQuote",
+		Input:              `"a\"b",c` + "\n",
+		Output:             [][]Column{{q(`a"b`), c("c")}},
+		EscapeChar:         '\\',
+		DisableDoubleQuote: true,
+	}, {
+		Name:       "EscapedEscape",
+		Input:      `"a\\b","c\,d"`,
+		Output:     [][]Column{{q(`a\b`), q("c,d")}},
+		EscapeChar: '\\',
+	}, {
+		Name:       "EscapedNewline",
+		Input:      "\"a\\\nb\",c\n",
+		Output:     [][]Column{{q("a\nb"), c("c")}},
+		EscapeChar: '\\',
+	}, {
+		Name:       "EscapedCRLF",
+		Input:      "\"a\\\r\nb\",c\r\n",
+		Output:     [][]Column{{q("a\nb"), c("c")}},
+		EscapeChar: '\\',
+	}, {
+		Name:       "EscapedCR",
+		Input:      "\"a\\\rb\",c\n",
+		Output:     [][]Column{{q("a\rb"), c("c")}},
+		EscapeChar: '\\',
+	}, {
+		Name:       "EscapedNewlineAtEOF",
+		Input:      "\"a\\\n",
+		Error:      &ParseError{StartLine: 1, Line: 2, Column: 0, Err: ErrQuote},
+		EscapeChar: '\\',
+	}, {
+		Name:               "LazyEscapedQuote",
+		Input:              `"a\"b" c",d` + "\n",
+		Output:             [][]Column{{q(`a"b" c`), c("d")}},
+		EscapeChar:         '\\',
+		DisableDoubleQuote: true,
+		LazyQuotes:         true,
+	}, {
+		Name:       "LazyEscapeInUnquotedField",
+		Input:      `a\"b,c` + "\n",
+		Output:     [][]Column{{c(`a\"b`), c("c")}},
+		EscapeChar: '\\',
+		LazyQuotes: true,
+	}, {
+		Name:       "EscapeInUnquotedField",
+		Input:      `a\b,c`,
+		Output:     [][]Column{{c(`a\b`), c("c")}},
+		EscapeChar: '\\',
+	}, {
+		Name:       "EscapeWithDoubleQuote",
+		Input:      `"a""b\"c"`,
+		Output:     [][]Column{{q(`a"b"c`)}},
+		EscapeChar: '\\',
+	}, {
+		Name:               "DisableDoubleQuote",
+		Input:              `"a""b"`,
+		Output:             [][]Column{{q(`a""b`)}},
+		DisableDoubleQuote: true,
+	}, {
+		Name:               "DisableDoubleQuoteEnd",
+		Input:              `"a""","b"""` + "\n",
+		Output:             [][]Column{{q(`a""`), q(`b""`)}},
+		DisableDoubleQuote: true,
+	}, {
+		Name:               "DisableDoubleQuoteBare",
+		Input:              `"a"b"`,
+		Error:              &ParseError{StartLine: 1, Line: 1, Column: 2, Err: ErrQuote},
+		DisableDoubleQuote: true,
+	}, {
+		Name:               "LazyDisableDoubleQuote",
+		Input:              `"a""b"`,
+		Output:             [][]Column{{q(`a""b`)}},
+		LazyQuotes:         true,
+		DisableDoubleQuote: true,
+	}, {
+		Name:         "MaxFieldSizeExact",
+		Input:        "abc,de\n\"fgh\",\"i\"\"j\"\n",
+		Output:       [][]Column{{c("abc"), c("de")}, {q("fgh"), q(`i"j`)}},
+		MaxFieldSize: 3,
+	}, {
+		Name:         "MaxFieldSizeUnquoted",
+		Input:        "ab,abcd,e\n",
+		Error:        &ParseError{StartLine: 1, Line: 1, Column: 6, Err: ErrFieldTooLarge},
+		MaxFieldSize: 3,
+	}, {
+		Name:         "MaxFieldSizeQuoted",
+		Input:        "\"abcd\"\n",
+		Error:        &ParseError{StartLine: 1, Line: 1, Column: 4, Err: ErrFieldTooLarge},
+		MaxFieldSize: 3,
+	}, {
+		Name:         "MaxFieldSizeDoubledQuote",
+		Input:        `"ab""c"`,
+		Error:        &ParseError{StartLine: 1, Line: 1, Column: 5, Err: ErrFieldTooLarge},
+		MaxFieldSize: 3,
+	}, {
+		Name:         "MaxFieldSizeMultiLine",
+		Input:        "x\n\"ab\ncd\"\n",
+		Error:        &ParseError{StartLine: 2, Line: 3, Column: 1, Err: ErrFieldTooLarge},
+		MaxFieldSize: 4,
+	}, {
+		Name:         "MaxFieldSizeUnterminated",
+		Input:        "\"" + strings.Repeat("x\n", 1000),
+		Error:        &ParseError{StartLine: 1, Line: 6, Column: 0, Err: ErrFieldTooLarge},
+		MaxFieldSize: 10,
+	}, {
+		Name:         "NegativeMaxFieldSize",
+		MaxFieldSize: -1,
+		Error:        errNegativeMaxFieldSize,
+	}, {
+		Name:              "TrimTrailingSpace",
+		Input:             "a ,b\t,\"c \",d \n",
+		Output:            [][]Column{{c("a"), c("b"), q("c "), c("d")}},
+		TrimTrailingSpace: true,
+	}, {
+		Name:              "TrimTrailingSpaceOnly",
+		Input:             " a , b \n",
+		Output:            [][]Column{{c(" a"), c(" b")}},
+		TrimTrailingSpace: true,
+	}, {
+		Name:              "TrimBothSpace",
+		Input:             "  a  ,\t\"b\",  c d  \n",
+		Output:            [][]Column{{c("a"), q("b"), c("c d")}},
+		TrimLeadingSpace:  true,
+		TrimTrailingSpace: true,
+	}, {
+		Name:              "TrimTrailingSpaceMultiByte",
+		Input:             "a\u00a0\u3000,b\u2003\u00e9\u2003\n",
+		Output:            [][]Column{{c("a"), c("b\u2003\u00e9")}},
+		TrimTrailingSpace: true,
+	}, {
+		Name:              "TrimTrailingSpaceToEmpty",
+		Input:             "a,   ,\u3000\t\n",
+		Output:            [][]Column{{c("a"), c(""), c("")}},
+		TrimTrailingSpace: true,
+	}, {
+		Name:              "TrimTrailingSpaceTabComma",
+		Input:             "a  \tb \n",
+		Output:            [][]Column{{c("a"), c("b")}},
+		Comma:             '\t',
+		TrimTrailingSpace: true,
+	}, {
+		Name:           "SanitizeFields",
+		Input:          "a\x00b,\"c\u00a0\u00a0d\",\u202ee\n",
+		Output:         [][]Column{{c("ab"), q("c d"), c("e")}},
+		SanitizeFields: true,
 	}, {
 		Name:  "BadComma1",
 		Comma: '\n',
@@ -361,6 +697,12 @@ x,,,
 		Name:  "BadComma3",
 		Comma: '"',
 		Error: errInvalidDelim,
+	}, {
+		Name:   "QuoteAsComma",
+		Input:  "a\"'b\"c'\"'d''e'\n",
+		Output: [][]Column{{c("a"), q(`b"c`), q("d'e")}},
+		Comma:  '"',
+		Quote:  '\'',
 	}, {
 		Name:  "BadComma4",
 		Comma: utf8.RuneError,
@@ -377,6 +719,14 @@ x,,,
 		Name:    "BadComment3",
 		Comment: utf8.RuneError,
 		Error:   errInvalidDelim,
+	}, {
+		Name:       "BadEscape1",
+		EscapeChar: ',',
+		Error:      errInvalidDelim,
+	}, {
+		Name:       "BadEscape2",
+		EscapeChar: '"',
+		Error:      errInvalidDelim,
 	}, {
 		Name:    "BadCommaComment",
 		Comma:   'X',
@@ -392,6 +742,9 @@ x,,,
 				r.Comma = tt.Comma
 			}
 			r.Comment = tt.Comment
+			if tt.Quote != 0 {
+				r.Quote = tt.Quote
+			}
 			if tt.UseFieldsPerRecord {
 				r.FieldsPerRecord = tt.FieldsPerRecord
 			} else {
@@ -399,7 +752,16 @@ x,,,
 			}
 			r.LazyQuotes = tt.LazyQuotes
 			r.TrimLeadingSpace = tt.TrimLeadingSpace
+			r.TrimTrailingSpace = tt.TrimTrailingSpace
+			r.SanitizeFields = tt.SanitizeFields
 			r.ReuseRecord = tt.ReuseRecord
+			r.Unescape = tt.Unescape
+			r.EscapeChar = tt.EscapeChar
+			r.DisableDoubleQuoteEscape = tt.DisableDoubleQuote
+			r.MaxFieldSize = tt.MaxFieldSize
+			r.MergeConsecutiveDelimiters = tt.MergeDelimiters
+			r.AllowBareNewlines = tt.BareNewlines
+			r.NormalizeCRLF = tt.NormalizeCRLF
 
 			out, err := r.ReadAll()
 			if !reflect.DeepEqual(err, tt.Error) {
@@ -411,6 +773,736 @@ x,,,
 	}
 }
 
+func TestParseErrorIs(t *testing.T) {
+	_, err := NewReader(strings.NewReader("a,b\n\"c\",d\"e\n")).ReadAll()
+	if err == nil {
+		t.Fatal("ReadAll() succeeded, want error")
+	}
+	wrapped := fmt.Errorf("import: %w", err)
+	tests := []struct {
+		Target error
+		Want   bool
+	}{
+		{ErrBareQuote, true},
+		{ErrQuote, false},
+		{&ParseError{}, true},
+		{&ParseError{Err: ErrBareQuote}, true},
+		{&ParseError{StartLine: 2, Line: 2, Column: 5, Err: ErrBareQuote}, true},
+		{&ParseError{Line: 2}, true},
+		{&ParseError{Line: 1}, false},
+		{&ParseError{Column: 4}, false},
+		{&ParseError{StartLine: 3}, false},
+		{&ParseError{Line: 2, Err: ErrFieldCount}, false},
+		{errors.New(ErrBareQuote.Error()), false},
+	}
+	for _, tt := range tests {
+		if got := errors.Is(wrapped, tt.Target); got != tt.Want {
+			t.Errorf("errors.Is(%v, %v) = %v, want %v", wrapped, tt.Target, got, tt.Want)
+		}
+	}
+
+	var perr *ParseError
+	if !errors.As(wrapped, &perr) {
+		t.Fatalf("errors.As(%v) failed", wrapped)
+	}
+	if perr.StartLine != 2 || perr.Line != 2 || perr.Column != 5 || perr.Err != ErrBareQuote {
+		t.Errorf("errors.As() = %+v", perr)
+	}
+
+	_, err = NewReader(strings.NewReader("a,b\nc\n")).ReadAll()
+	if !errors.Is(err, ErrFieldCount) || !errors.Is(err, &ParseError{Line: 2, Err: ErrFieldCount}) {
+		t.Errorf("error %v does not match ErrFieldCount on line 2", err)
+	}
+
+	r := NewReader(strings.NewReader("a\n"))
+	r.IgnorePanic = true
+	r.Unescape = func(string) string { panic("boom") }
+	if _, err := r.Read(); !errors.Is(err, &ParseError{Line: 1, Err: ErrHookPanic}) {
+		t.Errorf("error %v does not match ErrHookPanic on line 1", err)
+	}
+}
+
+func TestIgnorePanic(t *testing.T) {
+	panicky := func(s string) string {
+		if s == "boom" {
+			panic("hook exploded")
+		}
+		return s
+	}
+
+	r := NewReader(strings.NewReader("a,b\nc,boom\n"))
+	r.Unescape = panicky
+	r.IgnorePanic = true
+	if _, err := r.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	_, err := r.Read()
+	if !errors.Is(err, ErrHookPanic) {
+		t.Fatalf("Read() error = %v, want ErrHookPanic", err)
+	}
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Line != 2 {
+		t.Errorf("Read() error = %#v, want ParseError on line 2", err)
+	}
+	if !strings.Contains(err.Error(), "hook exploded") {
+		t.Errorf("Read() error %q does not contain the panic value", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected panic with IgnorePanic unset")
+		}
+	}()
+	r = NewReader(strings.NewReader("boom\n"))
+	r.Unescape = panicky
+	r.Read()
+}
+
+func TestRecordPrealloc(t *testing.T) {
+	// free is a minimal pool of records handed back by the reader.
+	var free [][]Column
+	var calls, recycled int
+	prealloc := func(prev []Column) []Column {
+		calls++
+		if prev != nil {
+			recycled++
+			free = append(free, prev)
+		}
+		if n := len(free); n > 0 {
+			record := free[n-1]
+			free = free[:n-1]
+			return record
+		}
+		return make([]Column, 0, 4)
+	}
+
+	r := NewReader(strings.NewReader("a,b\nc,d\n"))
+	r.RecordPrealloc = prealloc
+	first, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if !reflect.DeepEqual(first, []Column{c("a"), c("b")}) {
+		t.Errorf("Read() = %v", first)
+	}
+	firstPtr := &first[0]
+	second, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if !reflect.DeepEqual(second, []Column{c("c"), c("d")}) {
+		t.Errorf("Read() = %v", second)
+	}
+	if &second[0] != firstPtr {
+		t.Error("Read() did not store the record in the preallocated slice")
+	}
+	if calls != 2 || recycled != 1 {
+		t.Errorf("RecordPrealloc called %d times with %d previous records, want 2 and 1", calls, recycled)
+	}
+
+	// ReuseRecord takes precedence.
+	calls = 0
+	r = NewReader(strings.NewReader("a,b\n"))
+	r.RecordPrealloc = prealloc
+	r.ReuseRecord = true
+	r.Read()
+	if calls != 0 {
+		t.Errorf("RecordPrealloc called %d times with ReuseRecord set", calls)
+	}
+
+	// In steady state, the only allocation per record is its string data,
+	// the same as with ReuseRecord.
+	allocs := func(init func(*Reader)) float64 {
+		r := NewReader(&nTimes{s: "xx,yy,zz\n", n: 1000})
+		init(r)
+		r.Read()
+		return testing.AllocsPerRun(100, func() { r.Read() })
+	}
+	pooled := allocs(func(r *Reader) { r.RecordPrealloc = prealloc })
+	reused := allocs(func(r *Reader) { r.ReuseRecord = true })
+	if pooled > reused {
+		t.Errorf("Read() with RecordPrealloc: %v allocs per record, want %v", pooled, reused)
+	}
+}
+
+func TestPositions(t *testing.T) {
+	long := strings.Repeat("x", 5000)
+	tests := []struct {
+		Name             string
+		Input            string
+		Comma            rune
+		TrimLeadingSpace bool
+		Raw              [][]string // Input[StartOffset:EndOffset] of each field
+	}{{
+		Name:  "Simple",
+		Input: "a,bb,ccc\nd,,f",
+		Raw:   [][]string{{"a", "bb", "ccc"}, {"d", "", "f"}},
+	}, {
+		Name:  "MultiByteComma",
+		Input: "aλbbλ\"c\"\nλ\n",
+		Comma: 'λ',
+		Raw:   [][]string{{"a", "bb", `"c"`}, {"", ""}},
+	}, {
+		Name:  "QuotedNewline",
+		Input: "\"a\nb\",c\r\n\"d\r\n\r\ne\"\r\n",
+		Raw:   [][]string{{"\"a\nb\"", "c"}, {"\"d\r\n\r\ne\""}},
+	}, {
+		Name:  "BlankLines",
+		Input: "\n\na,b\n\n\"c\"\n",
+		Raw:   [][]string{{"a", "b"}, {`"c"`}},
+	}, {
+		Name:             "LeadingSpace",
+		Input:            "a,  b, \"c\"\n",
+		TrimLeadingSpace: true,
+		Raw:              [][]string{{"a", "  b", ` "c"`}},
+	}, {
+		Name:  "BufferBoundary",
+		Input: long + ",\"" + long + "\n" + long + "\"," + long + "\n",
+		Raw:   [][]string{{long, "\"" + long + "\n" + long + "\"", long}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			if tt.Comma != 0 {
+				r.Comma = tt.Comma
+			}
+			r.FieldsPerRecord = -1
+			r.TrimLeadingSpace = tt.TrimLeadingSpace
+			for i, want := range tt.Raw {
+				record, err := r.Read()
+				if err != nil {
+					t.Fatalf("Read() error: %v", err)
+				}
+				pos := r.Positions()
+				if len(pos) != len(record) {
+					t.Fatalf("record %d: %d positions for %d fields", i, len(pos), len(record))
+				}
+				for j, p := range pos {
+					if got := tt.Input[p.StartOffset:p.EndOffset]; got != want[j] {
+						t.Errorf("record %d, field %d: input[%d:%d] = %q, want %q", i, j, p.StartOffset, p.EndOffset, got, want[j])
+					}
+				}
+			}
+		})
+	}
+}
+
+// mysqlUnescape decodes the backslash escapes of MySQL's OUTFILE format.
+func mysqlUnescape(s string) string {
+	if !strings.ContainsRune(s, '\\') {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case '0':
+			b.WriteByte(0)
+		case 'b':
+			b.WriteByte('\b')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'Z':
+			b.WriteByte(0x1a)
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+//go:embed testdata/fs.csv
+var testFS embed.FS
+
+func TestNewFSReader(t *testing.T) {
+	r, err := NewFSReader(testFS, "testdata/fs.csv")
+	if err != nil {
+		t.Fatalf("NewFSReader() error: %v", err)
+	}
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := [][]Column{
+		{c("name"), c("lang")},
+		{q("Rob Pike"), c("go")},
+		{c("Ken Thompson"), q("C")},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAll() output:\ngot  %v\nwant %v", out, want)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("Close() error: %v", err)
+	}
+
+	if _, err := NewFSReader(testFS, "testdata/missing.csv"); err == nil {
+		t.Error("NewFSReader() with missing file: expected error")
+	}
+}
+
+func TestForEach(t *testing.T) {
+	const input = "a,1\n\"b\",2\nc,3\n"
+
+	r := NewReader(strings.NewReader(input))
+	r.ReuseRecord = true
+	var got []string
+	err := r.ForEach(func(record []Column) error {
+		got = append(got, record[0].Value+record[1].Value)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ForEach() error: %v", err)
+	}
+	if want := []string{"a1", "b2", "c3"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ForEach() visited %q, want %q", got, want)
+	}
+
+	stop := errors.New("stop")
+	r = NewReader(strings.NewReader(input))
+	n := 0
+	err = r.ForEach(func(record []Column) error {
+		n++
+		if record[0].Value == "b" {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("ForEach() error = %v, want %v", err, stop)
+	}
+	if n != 2 {
+		t.Errorf("ForEach() called fn %d times, want 2", n)
+	}
+
+	r = NewReader(strings.NewReader("a,b\n\"c\n"))
+	n = 0
+	err = r.ForEach(func([]Column) error { n++; return nil })
+	var perr *ParseError
+	if !errors.As(err, &perr) || perr.Err != ErrQuote {
+		t.Errorf("ForEach() error = %v, want ParseError wrapping %v", err, ErrQuote)
+	}
+	if n != 1 {
+		t.Errorf("ForEach() called fn %d times, want 1", n)
+	}
+}
+
+func TestReadAllFiltered(t *testing.T) {
+	r := NewReader(strings.NewReader("a,1\n\"b\",2\nc,3\n"))
+	r.ReuseRecord = true
+	out, err := r.ReadAllFiltered(func(record []Column) bool {
+		return record[1].Value != "2"
+	})
+	if err != nil {
+		t.Fatalf("ReadAllFiltered() error: %v", err)
+	}
+	want := [][]Column{{c("a"), c("1")}, {c("c"), c("3")}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAllFiltered() output:\ngot  %v\nwant %v", out, want)
+	}
+
+	r = NewReader(strings.NewReader("a,b\nc\n"))
+	r.FieldsPerRecord = 2
+	if _, err := r.ReadAllFiltered(func([]Column) bool { return true }); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("ReadAllFiltered() error = %v, want %v", err, ErrFieldCount)
+	}
+}
+
+func TestReadN(t *testing.T) {
+	const input = "a,1\n\"b\",2\nc,3\n\nd,4\n# note\ne,5\nf,\"6\"\ng,7\n"
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	want, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	for _, reuse := range []bool{false, true} {
+		for _, n := range []int{1, 2, 3, 7, 10} {
+			r = NewReader(strings.NewReader(input))
+			r.Comment = '#'
+			r.ReuseRecord = reuse
+			var out [][]Column
+			for {
+				page, err := r.ReadN(n)
+				if err == io.EOF {
+					if page != nil {
+						t.Errorf("ReadN(%d) = %v with io.EOF, want nil", n, page)
+					}
+					break
+				}
+				if err != nil {
+					t.Fatalf("ReadN(%d) error: %v", n, err)
+				}
+				if len(page) == 0 || len(page) > n {
+					t.Fatalf("ReadN(%d) returned %d records", n, len(page))
+				}
+				out = append(out, page...)
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf("ReadN(%d) with ReuseRecord=%v pages:\ngot  %v\nwant %v", n, reuse, out, want)
+			}
+		}
+	}
+
+	r = NewReader(strings.NewReader("a,b\nc,d\ne\n"))
+	r.FieldsPerRecord = 2
+	page, err := r.ReadN(5)
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("ReadN() error = %v, want %v", err, ErrFieldCount)
+	}
+	if want := [][]Column{{c("a"), c("b")}, {c("c"), c("d")}}; !reflect.DeepEqual(page, want) {
+		t.Errorf("ReadN() with error = %v, want %v", page, want)
+	}
+}
+
+func TestPeek(t *testing.T) {
+	const input = "# comment\na,1\n\"b\",2\n\nc,3\n"
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	r.ReuseRecord = true
+
+	peeked, err := r.Peek(2)
+	if err != nil {
+		t.Fatalf("Peek() error: %v", err)
+	}
+	want := [][]Column{{c("a"), c("1")}, {q("b"), c("2")}, {c("c"), c("3")}}
+	if !reflect.DeepEqual(peeked, want[:2]) {
+		t.Errorf("Peek(2) = %v, want %v", peeked, want[:2])
+	}
+	if peeked, _ := r.Peek(1); !reflect.DeepEqual(peeked, want[:1]) {
+		t.Errorf("Peek(1) = %v, want %v", peeked, want[:1])
+	}
+	if peeked, _ := r.Peek(5); !reflect.DeepEqual(peeked, want) {
+		t.Errorf("Peek(5) = %v, want %v", peeked, want)
+	}
+
+	for i, wantRecord := range want {
+		record, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		if !reflect.DeepEqual(record, wantRecord) {
+			t.Errorf("Read() = %v, want %v", record, wantRecord)
+		}
+		pos := r.Positions()
+		if got := input[pos[0].StartOffset:pos[1].EndOffset]; got != strings.Split(input, "\n")[[]int{1, 2, 4}[i]] {
+			t.Errorf("record %d: Positions() cover %q", i, got)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Errorf("Read() error = %v, want io.EOF", err)
+	}
+	// Reusing records must not overwrite what Peek returned.
+	if !reflect.DeepEqual(peeked, want[:2]) {
+		t.Errorf("records returned by Peek changed to %v", peeked)
+	}
+	if peeked, err := r.Peek(1); peeked != nil || err != nil {
+		t.Errorf("Peek() at EOF = %v, %v, want nil, nil", peeked, err)
+	}
+}
+
+func TestPeekError(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\nc,d\ne,\"f\ng\"h\n"))
+	peeked, err := r.Peek(10)
+	wantErr := &ParseError{StartLine: 3, Line: 4, Column: 1, Err: ErrQuote}
+	if !reflect.DeepEqual(err, wantErr) {
+		t.Fatalf("Peek() error = %v, want %v", err, wantErr)
+	}
+	if len(peeked) != 2 {
+		t.Fatalf("Peek() returned %d records, want 2", len(peeked))
+	}
+	out, err := r.ReadAll()
+	if !reflect.DeepEqual(err, wantErr) {
+		t.Errorf("ReadAll() error = %v, want %v", err, wantErr)
+	}
+	if out != nil {
+		t.Errorf("ReadAll() = %v, want nil", out)
+	}
+
+	r = NewReader(strings.NewReader("a,b\nc\n"))
+	if _, err := r.Peek(2); !errors.Is(err, ErrFieldCount) {
+		t.Fatalf("Peek() error = %v, want %v", err, ErrFieldCount)
+	}
+	r.Read()
+	record, err := r.Read()
+	if !errors.Is(err, ErrFieldCount) || !reflect.DeepEqual(record, []Column{c("c")}) {
+		t.Errorf("Read() = %v, %v, want [c], %v", record, err, ErrFieldCount)
+	}
+}
+
+func TestStripBOM(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Input    string
+		StripBOM bool
+		Output   [][]Column
+	}{
+		{Name: "Strip", Input: "\ufeffa,b\nc,d\n", StripBOM: true, Output: [][]Column{{c("a"), c("b")}, {c("c"), c("d")}}},
+		{Name: "Keep", Input: "\ufeffa,b\n", Output: [][]Column{{c("\ufeffa"), c("b")}}},
+		{Name: "Quoted", Input: "\ufeff\"a\",b\n", StripBOM: true, Output: [][]Column{{q("a"), c("b")}}},
+		{Name: "OnlyFirst", Input: "a\n\ufeffb\n", StripBOM: true, Output: [][]Column{{c("a")}, {c("\ufeffb")}}},
+		{Name: "NoBOM", Input: "a,b\n", StripBOM: true, Output: [][]Column{{c("a"), c("b")}}},
+		{Name: "OnlyBOM", Input: "\ufeff", StripBOM: true},
+		{Name: "Empty", Input: "", StripBOM: true},
+		{Name: "Short", Input: "\xef\xbb", StripBOM: true, Output: [][]Column{{c("\xef\xbb")}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			r.StripBOM = tt.StripBOM
+			out, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if !reflect.DeepEqual(out, tt.Output) {
+				t.Errorf("ReadAll() output:\ngot  %v\nwant %v", out, tt.Output)
+			}
+			if n := r.Metrics().BytesRead; n != int64(len(tt.Input)) {
+				t.Errorf("BytesRead = %d, want %d", n, len(tt.Input))
+			}
+		})
+	}
+
+	r := NewReader(strings.NewReader("\ufeffab,c\n"))
+	r.StripBOM = true
+	r.Read()
+	if got, want := r.Positions(), []ColumnPosition{{3, 5}, {6, 7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Positions() = %v, want %v", got, want)
+	}
+}
+
+func TestCaptureRaw(t *testing.T) {
+	tests := []struct {
+		Name  string
+		Input string
+		Raw   [][]string
+	}{
+		{Name: "Simple", Input: "a,b\n", Raw: [][]string{{"a", "b"}}},
+		{Name: "DoubleQuote", Input: `"a""b",c` + "\n", Raw: [][]string{{`"a""b"`, "c"}}},
+		{Name: "Empty", Input: `,"",` + "\n", Raw: [][]string{{"", `""`, ""}}},
+		{Name: "MultiLine", Input: "x,\"a\r\nb\"\ny\n", Raw: [][]string{{"x", "\"a\nb\""}, {"y"}}},
+		{Name: "NoNewline", Input: `a,"b"`, Raw: [][]string{{"a", `"b"`}}},
+		{Name: "SecondRecord", Input: "ab\r\n\"c\",d\r\n", Raw: [][]string{{"ab"}, {`"c"`, "d"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			r.CaptureRaw = true
+			r.FieldsPerRecord = -1
+			out, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			var raw [][]string
+			for _, rec := range out {
+				var fields []string
+				for _, col := range rec {
+					fields = append(fields, col.OriginalBytes)
+				}
+				raw = append(raw, fields)
+			}
+			if !reflect.DeepEqual(raw, tt.Raw) {
+				t.Errorf("OriginalBytes:\ngot  %q\nwant %q", raw, tt.Raw)
+			}
+		})
+	}
+
+	r := NewReader(strings.NewReader(`"a""b"` + "\n"))
+	rec, err := r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if rec[0].Value != `a"b` || rec[0].OriginalBytes != "" {
+		t.Errorf("Read() without CaptureRaw = %+v, want Value a\"b and no OriginalBytes", rec[0])
+	}
+
+	// Captured columns stay comparable, also as map keys.
+	r = NewReader(strings.NewReader(`"a""b","a""b"` + "\n"))
+	r.CaptureRaw = true
+	rec, err = r.Read()
+	if err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	seen := map[Column]bool{rec[0]: true}
+	if rec[0] != rec[1] || !seen[rec[1]] {
+		t.Errorf("captured columns %+v and %+v compare unequal", rec[0], rec[1])
+	}
+}
+
+func TestSkipRows(t *testing.T) {
+	const input = "Report \"Q1\" 2021\nExported by: admin, ops\n\n# note\nname,lang\nRob,go\nKen,C\"\nDennis,C\n"
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	n, err := r.SkipRows(2)
+	if n != 2 || err != nil {
+		t.Fatalf("SkipRows(2) = %d, %v, want 2, nil", n, err)
+	}
+	h, err := NewHeaderReader(r)
+	if err != nil {
+		t.Fatalf("NewHeaderReader() error: %v", err)
+	}
+	if want := []string{"name", "lang"}; !reflect.DeepEqual(h.Headers(), want) {
+		t.Errorf("Headers() = %q, want %q", h.Headers(), want)
+	}
+	if _, err := h.ReadMap(); err != nil {
+		t.Fatalf("ReadMap() error: %v", err)
+	}
+	// The line numbers include the skipped rows.
+	_, err = h.ReadMap()
+	want := &ParseError{StartLine: 7, Line: 7, Column: 5, FieldName: "lang", Err: ErrBareQuote}
+	if !errors.Is(err, want) {
+		t.Errorf("ReadMap() error = %v, want %v", err, want)
+	}
+	if got := r.Metrics().RecordsRead; got != 2 {
+		t.Errorf("RecordsRead = %d, want 2", got)
+	}
+	if r.LazyQuotes || r.FieldsPerRecord != 2 {
+		t.Errorf("LazyQuotes = %v, FieldsPerRecord = %d after SkipRows", r.LazyQuotes, r.FieldsPerRecord)
+	}
+
+	r = NewReader(strings.NewReader("a\nb\n"))
+	if n, err := r.SkipRows(3); n != 2 || err != io.EOF {
+		t.Errorf("SkipRows(3) = %d, %v, want 2, io.EOF", n, err)
+	}
+
+	r = NewReader(strings.NewReader("a\nb\nc\n"))
+	r.Peek(2)
+	if n, err := r.SkipRows(2); n != 2 || err != nil {
+		t.Errorf("SkipRows(2) after Peek = %d, %v, want 2, nil", n, err)
+	}
+	if record, err := r.Read(); err != nil || !reflect.DeepEqual(record, []Column{c("c")}) {
+		t.Errorf("Read() = %v, %v, want [c]", record, err)
+	}
+}
+
+// chunkReader returns one chunk per call to Read, calling
+// after(i) once the i'th chunk has been returned.
+type chunkReader struct {
+	chunks []string
+	after  func(i int)
+	n      int
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if r.n == len(r.chunks) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[r.n])
+	if r.after != nil {
+		r.after(r.n)
+	}
+	r.n++
+	return n, nil
+}
+
+func TestReadContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	r := NewReader(&chunkReader{
+		chunks: []string{"x,y\n", "a,\"b\n", "c\"\n"},
+		after: func(i int) {
+			if i == 1 {
+				cancel()
+			}
+		},
+	})
+	record, err := r.ReadContext(ctx)
+	if err != nil {
+		t.Fatalf("ReadContext() error: %v", err)
+	}
+	if want := []Column{c("x"), c("y")}; !reflect.DeepEqual(record, want) {
+		t.Errorf("ReadContext() = %v, want %v", record, want)
+	}
+	// The context is cancelled in the middle of the second record.
+	record, err = r.ReadContext(ctx)
+	if err != context.Canceled || record != nil {
+		t.Errorf("ReadContext() = %v, %v, want nil, %v", record, err, context.Canceled)
+	}
+	if _, err := r.ReadContext(ctx); err != context.Canceled {
+		t.Errorf("ReadContext() error = %v, want %v", err, context.Canceled)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	r = NewReader(strings.NewReader("a,b\n"))
+	if _, err := r.ReadContext(ctx); err != context.DeadlineExceeded {
+		t.Errorf("ReadContext() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(time.Hour))
+	defer cancel()
+	r = NewReader(strings.NewReader("a,b\n"))
+	if _, err := r.ReadContext(ctx); err != nil {
+		t.Errorf("ReadContext() error: %v", err)
+	}
+	if _, err := r.ReadContext(ctx); err != io.EOF {
+		t.Errorf("ReadContext() error = %v, want %v", err, io.EOF)
+	}
+}
+
+func TestRecordChannel(t *testing.T) {
+	r := NewReader(strings.NewReader("a,1\nb,2\nc,3\n"))
+	r.ReuseRecord = true
+	r.RecordChannelSize = 1
+	var out [][]Column
+	for record := range r.RecordChannel(context.Background()) {
+		out = append(out, record)
+	}
+	if err := r.Err(); err != nil {
+		t.Fatalf("Err() = %v", err)
+	}
+	want := [][]Column{{c("a"), c("1")}, {c("b"), c("2")}, {c("c"), c("3")}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("records:\ngot  %v\nwant %v", out, want)
+	}
+
+	r = NewReader(strings.NewReader("a,1\nb\n"))
+	n := 0
+	for range r.RecordChannel(context.Background()) {
+		n++
+	}
+	if n != 1 || !errors.Is(r.Err(), ErrFieldCount) {
+		t.Errorf("got %d records and error %v, want 1 and %v", n, r.Err(), ErrFieldCount)
+	}
+}
+
+func TestRecordChannelCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := NewReader(&nTimes{s: "a,b,c\n", n: 1 << 20})
+	ch := r.RecordChannel(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-ch // Wait until the producer is running.
+		cancel()
+		for range ch {
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("channel not closed after cancellation")
+	}
+	if err := r.Err(); err != context.Canceled {
+		t.Errorf("Err() = %v, want %v", err, context.Canceled)
+	}
+}
+
 // nTimes is an io.Reader which yields the string s n times.
 type nTimes struct {
 	s   string
@@ -537,3 +1629,268 @@ func q(s string) Column {
 		Quoted: true,
 	}
 }
+
+func TestReadSingleColumn(t *testing.T) {
+	r := NewReader(strings.NewReader("a\n\"b,c\"\n\nd\n"))
+	got, err := r.ReadSingleColumn()
+	if err != nil {
+		t.Fatalf("ReadSingleColumn() error: %v", err)
+	}
+	if want := []string{"a", "b,c", "d"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadSingleColumn() = %q, want %q", got, want)
+	}
+
+	r = NewReader(strings.NewReader("a\nb,c\nd\n"))
+	r.FieldsPerRecord = -1
+	_, err = r.ReadSingleColumn()
+	if want := (&ParseError{StartLine: 2, Line: 2, Err: ErrFieldCount}); !reflect.DeepEqual(err, want) {
+		t.Errorf("ReadSingleColumn() error = %v, want %v", err, want)
+	}
+}
+
+func TestSetErrorHandler(t *testing.T) {
+	const input = "a,b\n" +
+		"c,\"d\n" +
+		"e,f\"g,h\n" +
+		"i,j,k\n" +
+		"l,m\n"
+	for _, reuse := range []bool{false, true} {
+		r := NewReader(strings.NewReader(input))
+		r.ReuseRecord = reuse
+		type call struct {
+			err    *ParseError
+			record []Column
+		}
+		var calls []call
+		r.SetErrorHandler(func(err *ParseError, record []Column) {
+			calls = append(calls, call{err, append([]Column(nil), record...)})
+		})
+		var out [][]Column
+		err := r.ForEach(func(record []Column) error {
+			out = append(out, append([]Column(nil), record...))
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ForEach() error: %v", err)
+		}
+		want := [][]Column{{c("a"), c("b")}, {c("l"), c("m")}}
+		if !reflect.DeepEqual(out, want) {
+			t.Errorf("records with ReuseRecord=%v:\ngot  %v\nwant %v", reuse, out, want)
+		}
+		wantCalls := []call{
+			{&ParseError{StartLine: 2, Line: 3, Column: 3, Err: ErrQuote}, []Column{c("c")}},
+			{&ParseError{StartLine: 4, Line: 4, Err: ErrFieldCount}, []Column{c("i"), c("j"), c("k")}},
+		}
+		if !reflect.DeepEqual(calls, wantCalls) {
+			t.Errorf("handler calls with ReuseRecord=%v:\ngot  %+v\nwant %+v", reuse, calls, wantCalls)
+		}
+	}
+}
+
+func TestInputOffset(t *testing.T) {
+	long := strings.Repeat("x", 3*4096+17) // Spans several read buffers.
+	// Records of 9 bytes, 11+len(long)+5 bytes with a comment and a
+	// blank line before it, and 7 bytes without a final line break.
+	input := "a,\"b\r\nc\"\n" +
+		"# comment\n\n" +
+		"\"" + long + "\",d\n" +
+		"\"e\"\"\",f"
+	ends := []int64{9, 9 + 11 + int64(len(long)) + 5, int64(len(input))}
+
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	if got := r.InputOffset(); got != 0 {
+		t.Errorf("InputOffset() before Read = %d, want 0", got)
+	}
+	for i, want := range ends {
+		if _, err := r.Read(); err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		if got := r.InputOffset(); got != want {
+			t.Errorf("InputOffset() after record %d = %d, want %d", i, got, want)
+		}
+	}
+	if _, err := r.Read(); err != io.EOF {
+		t.Fatalf("Read() error = %v, want io.EOF", err)
+	}
+	if got := r.InputOffset(); got != int64(len(input)) {
+		t.Errorf("InputOffset() at EOF = %d, want %d", got, len(input))
+	}
+
+	r = NewReader(strings.NewReader("\ufeffa,b\nc\"d\ne,f\n"))
+	r.StripBOM = true
+	r.Read()
+	if got := r.InputOffset(); got != 7 {
+		t.Errorf("InputOffset() after BOM and record = %d, want 7", got)
+	}
+	if _, err := r.Read(); !errors.Is(err, ErrBareQuote) {
+		t.Fatalf("Read() error = %v, want %v", err, ErrBareQuote)
+	}
+	if got := r.InputOffset(); got != 11 {
+		t.Errorf("InputOffset() after ParseError = %d, want 11", got)
+	}
+}
+
+func TestAllowBareNewlinesOffsets(t *testing.T) {
+	input := "1,hello\nworld\n2,foo\n"
+	r := NewReader(strings.NewReader(input))
+	r.FieldsPerRecord = 2
+	r.AllowBareNewlines = true
+	for i, want := range []struct {
+		offset    int64
+		positions []ColumnPosition
+	}{
+		{14, []ColumnPosition{{0, 1}, {2, 13}}},
+		{20, []ColumnPosition{{14, 15}, {16, 19}}},
+	} {
+		if _, err := r.Read(); err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		if got := r.InputOffset(); got != want.offset {
+			t.Errorf("InputOffset() after record %d = %d, want %d", i, got, want.offset)
+		}
+		if got := r.Positions(); !reflect.DeepEqual(got, want.positions) {
+			t.Errorf("Positions() of record %d = %v, want %v", i, got, want.positions)
+		}
+	}
+}
+
+func TestReaderClone(t *testing.T) {
+	null := "NULL"
+	r := NewReader(strings.NewReader("x;y\n"))
+	r.Comma = ';'
+	r.Comment = '#'
+	r.FieldsPerRecord = 2
+	r.TrimLeadingSpace = true
+	r.NullValue = &null
+	r.AddTransformer(0, func(col Column) Column {
+		col.Value = strings.ToUpper(col.Value)
+		return col
+	})
+	if _, err := r.Read(); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+
+	cl := r.Clone(strings.NewReader("# comment\na; NULL\n"))
+	cl.Comma = '|'
+	if r.Comma != ';' {
+		t.Errorf("Comma of original = %q after changing clone, want ';'", r.Comma)
+	}
+	cl.Comma = ';'
+	out, err := cl.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() of clone error: %v", err)
+	}
+	want := [][]Column{{c("A"), {IsNull: true}}}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAll() of clone = %v, want %v", out, want)
+	}
+	if got := cl.InputOffset(); got != 18 {
+		t.Errorf("InputOffset() of clone = %d, want 18", got)
+	}
+}
+
+func TestReadStrings(t *testing.T) {
+	const input = "a,\"b\"\nNULL,\"c,d\"\ne\n"
+	r := NewReader(strings.NewReader(input))
+	null := "NULL"
+	r.NullValue = &null
+	peeked, err := r.Peek(1)
+	if err != nil {
+		t.Fatalf("Peek() error: %v", err)
+	}
+	out, err := r.ReadAllStrings()
+	want := [][]string{{"a", "b"}, {"", "c,d"}}
+	if !errors.Is(err, ErrFieldCount) || out != nil {
+		t.Errorf("ReadAllStrings() = %q, %v, want nil, %v", out, err, ErrFieldCount)
+	}
+	if !reflect.DeepEqual(peeked, [][]Column{{c("a"), q("b")}}) {
+		t.Errorf("ReadAllStrings() changed the record returned by Peek to %v", peeked)
+	}
+
+	r = NewReader(strings.NewReader(input))
+	r.NullValue = &null
+	r.FieldsPerRecord = -1
+	out, err = r.ReadAllStrings()
+	if err != nil {
+		t.Fatalf("ReadAllStrings() error: %v", err)
+	}
+	if want := append(want, []string{"e"}); !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAllStrings() = %q, want %q", out, want)
+	}
+
+	r = NewReader(strings.NewReader(input))
+	r.ReuseRecord = true
+	first, _ := r.ReadStrings()
+	second, _ := r.ReadStrings()
+	if !reflect.DeepEqual(first, want[0]) || !reflect.DeepEqual(second, []string{"NULL", "c,d"}) {
+		t.Errorf("ReadStrings() = %q, %q", first, second)
+	}
+	third, err := r.ReadStrings()
+	if !errors.Is(err, ErrFieldCount) || !reflect.DeepEqual(third, []string{"e"}) {
+		t.Errorf("ReadStrings() = %q, %v, want [e], %v", third, err, ErrFieldCount)
+	}
+	if out, err := r.ReadStrings(); out != nil || err != io.EOF {
+		t.Errorf("ReadStrings() at end = %q, %v, want nil, EOF", out, err)
+	}
+}
+
+func BenchmarkReadAllStrings(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := NewReader(strings.NewReader(benchmarkCSVData))
+		if _, err := r.ReadAllStrings(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkReadAllExtracted(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := NewReader(strings.NewReader(benchmarkCSVData))
+		records, err := r.ReadAll()
+		if err != nil {
+			b.Fatal(err)
+		}
+		out := make([][]string, len(records))
+		for j, record := range records {
+			out[j] = make([]string, len(record))
+			for k, col := range record {
+				out[j][k] = col.Value
+			}
+		}
+	}
+}
+
+func TestCollectComments(t *testing.T) {
+	const input = "#version: 2\n# exported 2020-01-02\r\na,b\n#mid\n\nc,d\n#end\n#\n# last"
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	r.CollectComments = true
+	steps := []struct {
+		record   []Column
+		err      error
+		comments []string
+	}{
+		{[]Column{c("a"), c("b")}, nil, []string{"version: 2", " exported 2020-01-02"}},
+		{[]Column{c("c"), c("d")}, nil, []string{"version: 2", " exported 2020-01-02", "mid"}},
+		{nil, io.EOF, []string{"version: 2", " exported 2020-01-02", "mid", "end", "", " last"}},
+	}
+	for i, step := range steps {
+		record, err := r.Read()
+		if err != step.err || !reflect.DeepEqual(record, step.record) {
+			t.Errorf("Read() %d = %v, %v, want %v, %v", i, record, err, step.record, step.err)
+		}
+		if !reflect.DeepEqual(r.Comments, step.comments) {
+			t.Errorf("Comments after Read() %d = %q, want %q", i, r.Comments, step.comments)
+		}
+	}
+
+	r = NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	r.ReadAll()
+	if r.Comments != nil {
+		t.Errorf("Comments without CollectComments = %q, want nil", r.Comments)
+	}
+}
@@ -0,0 +1,77 @@
+package csv
+
+import "io"
+
+// A Record is a record read by ReadRecord or ReadAllRecords together with
+// its location in the input.
+type Record struct {
+	Columns []Column
+
+	// StartLine and EndLine are the lines where the record starts and
+	// ends. They differ if a quoted field spans several lines.
+	StartLine int
+	EndLine   int
+
+	// SourceName is the Reader's SourceName.
+	SourceName string
+}
+
+// ReadRecord is like Read but returns the record along with its location
+// in the input. If Read would return a record along with an error, such
+// as for ErrFieldCount, ReadRecord does the same.
+func (r *Reader) ReadRecord() (Record, error) {
+	columns, err := r.Read()
+	if columns == nil {
+		return Record{}, err
+	}
+	return r.record(columns), err
+}
+
+// ReadAllRecords is like ReadAll but returns each record along with its
+// location in the input.
+func (r *Reader) ReadAllRecords() (records []Record, err error) {
+	for {
+		columns, err := r.readRecord(nil)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r.record(columns))
+	}
+}
+
+// record returns columns as a Record read at the current position of r.
+func (r *Reader) record(columns []Column) Record {
+	return Record{
+		Columns:    columns,
+		StartLine:  r.recLine,
+		EndLine:    r.endLine,
+		SourceName: r.SourceName,
+	}
+}
+
+// A RecordWithLine is a record read by ReadAllWithLineNumbers together
+// with the lines where it starts and ends.
+type RecordWithLine struct {
+	Record    []Column
+	StartLine int
+	EndLine   int
+}
+
+// ReadAllWithLineNumbers reads all the remaining records from r like
+// ReadAll and returns each along with its line numbers, so that a record
+// can be reported by its position in the input after validation. It is
+// like Reader.ReadAllRecords, without the source name.
+func ReadAllWithLineNumbers(r *Reader) ([]RecordWithLine, error) {
+	records, err := r.ReadAllRecords()
+	if err != nil {
+		return nil, err
+	}
+	out := make([]RecordWithLine, len(records))
+	for i, rec := range records {
+		out[i] = RecordWithLine{rec.Columns, rec.StartLine, rec.EndLine}
+	}
+	return out, nil
+}
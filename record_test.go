@@ -0,0 +1,90 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadRecord(t *testing.T) {
+	const input = "a,b\n\n# comment\n\"c\nd\r\ne\",f\ng,h\n"
+	want := []Record{
+		{Columns: []Column{c("a"), c("b")}, StartLine: 1, EndLine: 1, SourceName: "in.csv"},
+		{Columns: []Column{q("c\nd\ne"), c("f")}, StartLine: 4, EndLine: 6, SourceName: "in.csv"},
+		{Columns: []Column{c("g"), c("h")}, StartLine: 7, EndLine: 7, SourceName: "in.csv"},
+	}
+
+	r := NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	r.SourceName = "in.csv"
+	out, err := r.ReadAllRecords()
+	if err != nil {
+		t.Fatalf("ReadAllRecords() error: %v", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAllRecords() =\n%v\nwant\n%v", out, want)
+	}
+
+	// Records read ahead by Peek keep their lines.
+	r = NewReader(strings.NewReader(input))
+	r.Comment = '#'
+	r.SourceName = "in.csv"
+	if _, err := r.Peek(2); err != nil {
+		t.Fatalf("Peek() error: %v", err)
+	}
+	for i := range want {
+		rec, err := r.ReadRecord()
+		if err != nil {
+			t.Fatalf("ReadRecord() error: %v", err)
+		}
+		if !reflect.DeepEqual(rec, want[i]) {
+			t.Errorf("ReadRecord() = %v, want %v", rec, want[i])
+		}
+	}
+	if rec, err := r.ReadRecord(); err != io.EOF || !reflect.DeepEqual(rec, Record{}) {
+		t.Errorf("ReadRecord() at end = %v, %v, want zero Record, EOF", rec, err)
+	}
+}
+
+func TestReadRecordError(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\nc\n"))
+	r.ReadRecord()
+	rec, err := r.ReadRecord()
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("ReadRecord() error = %v, want %v", err, ErrFieldCount)
+	}
+	if want := (Record{Columns: []Column{c("c")}, StartLine: 2, EndLine: 2}); !reflect.DeepEqual(rec, want) {
+		t.Errorf("ReadRecord() = %v, want %v", rec, want)
+	}
+
+	r = NewReader(strings.NewReader("a,b\nc\n"))
+	if out, err := r.ReadAllRecords(); out != nil || !errors.Is(err, ErrFieldCount) {
+		t.Errorf("ReadAllRecords() = %v, %v, want nil, %v", out, err, ErrFieldCount)
+	}
+}
+
+func TestReadAllWithLineNumbers(t *testing.T) {
+	// Record 3 spans lines 4 to 6.
+	const input = "id,email\n1,a@example.com\n2,b@example.com\n3,\"multi\nline\nnote\"\n4,d@example.com\n"
+	want := []RecordWithLine{
+		{Record: []Column{c("id"), c("email")}, StartLine: 1, EndLine: 1},
+		{Record: []Column{c("1"), c("a@example.com")}, StartLine: 2, EndLine: 2},
+		{Record: []Column{c("2"), c("b@example.com")}, StartLine: 3, EndLine: 3},
+		{Record: []Column{c("3"), q("multi\nline\nnote")}, StartLine: 4, EndLine: 6},
+		{Record: []Column{c("4"), c("d@example.com")}, StartLine: 7, EndLine: 7},
+	}
+	out, err := ReadAllWithLineNumbers(NewReader(strings.NewReader(input)))
+	if err != nil {
+		t.Fatalf("ReadAllWithLineNumbers() error: %v", err)
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadAllWithLineNumbers() =\n%v\nwant\n%v", out, want)
+	}
+
+	_, err = ReadAllWithLineNumbers(NewReader(strings.NewReader("a,b\nc\n")))
+	if !errors.Is(err, &ParseError{Line: 2, Err: ErrFieldCount}) {
+		t.Errorf("ReadAllWithLineNumbers() error = %v, want ErrFieldCount on line 2", err)
+	}
+}
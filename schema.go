@@ -0,0 +1,278 @@
+package csv
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// A ColumnType is the Go type the values of a column are converted to.
+type ColumnType int
+
+const (
+	TypeString ColumnType = iota // string
+	TypeInt                      // int64
+	TypeFloat                    // float64
+	TypeBool                     // bool
+	TypeTime                     // time.Time
+)
+
+func (t ColumnType) String() string {
+	switch t {
+	case TypeString:
+		return "string"
+	case TypeInt:
+		return "int64"
+	case TypeFloat:
+		return "float64"
+	case TypeBool:
+		return "bool"
+	case TypeTime:
+		return "time.Time"
+	}
+	return "ColumnType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// A ColumnSchema describes one column of a CSV file.
+type ColumnSchema struct {
+	Name string
+	Type ColumnType
+
+	// TimeLayout is the layout passed to time.Parse for TypeTime columns.
+	// If empty, time.RFC3339 is used.
+	TimeLayout string
+
+	// The remaining fields are constraints checked by a Reader with the
+	// schema attached by SetSchema. The value of a field that is not
+	// Required may always be missing (see Column.IsMissing); the other
+	// constraints, including conversion to Type, only apply otherwise.
+
+	Required  bool           // The field must not be missing
+	MaxLength int            // If positive, the maximum length of the value in runes
+	Pattern   *regexp.Regexp // If non-nil, the value must match Pattern
+}
+
+// A Schema describes the columns of a CSV file.
+type Schema struct {
+	// ColumnNames, if non-nil, are the expected fields of the header row.
+	// A Reader with the schema attached by SetSchema checks the first
+	// record it reads against ColumnNames instead of Columns.
+	ColumnNames []string
+
+	Columns []ColumnSchema
+
+	// If StrictColumnCount is true, a record must have exactly one field
+	// per element of Columns. Each missing or extra field is reported as a
+	// violation of ErrColumnCount.
+	StrictColumnCount bool
+}
+
+// These are the errors that can be returned in ColumnError.Err, besides
+// errors converting a value to the column's Type.
+var (
+	ErrRequired        = errors.New("required value is missing")
+	ErrTooLong         = errors.New("value exceeds MaxLength")
+	ErrPatternMismatch = errors.New("value does not match pattern")
+	ErrColumnName      = errors.New("unexpected column name")
+	ErrColumnCount     = errors.New("wrong number of fields for schema")
+)
+
+var errEmptySchema = errors.New("csv: schema has no columns")
+
+// A ColumnError describes a field that violates its ColumnSchema.
+type ColumnError struct {
+	Index int    // Index of the field in the record
+	Name  string // Name of the column, if known
+	Value string // Value of the field
+	Err   error  // The violated constraint
+}
+
+func (e *ColumnError) Error() string {
+	return fmt.Sprintf("column %d (%q), value %q: %v", e.Index, e.Name, e.Value, e.Err)
+}
+
+func (e *ColumnError) Unwrap() error { return e.Err }
+
+// A ValidationError is returned by a Reader with a schema attached if a
+// record violates it. It lists every violation found in the record.
+type ValidationError struct {
+	StartLine int // Line where the record starts
+	Line      int // Line where the record ends
+	Columns   []*ColumnError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Columns))
+	for i, ce := range e.Columns {
+		msgs[i] = ce.Error()
+	}
+	return fmt.Sprintf("csv: record on line %d: invalid fields: %s", e.StartLine, strings.Join(msgs, "; "))
+}
+
+// Errors returns copies of the violations listed in e.Columns.
+func (e *ValidationError) Errors() []ColumnError {
+	errs := make([]ColumnError, len(e.Columns))
+	for i, ce := range e.Columns {
+		errs[i] = *ce
+	}
+	return errs
+}
+
+// SetSchema attaches s to r, or detaches the current schema if s is nil.
+// Read and ReadAll then check every record against s and return a
+// *ValidationError if it is violated. Read returns the record along with
+// the error. Missing fields at the end of a record are checked as empty;
+// fields beyond the last column of s are not checked.
+//
+// If s.ColumnNames is non-nil, the first record read after SetSchema is
+// taken as the header row and checked against s.ColumnNames only.
+//
+// ParseErrors returned by r carry the name of the field in error in
+// FieldName, taken from s.ColumnNames or, if it is nil, s.Columns.
+func (r *Reader) SetSchema(s *Schema) {
+	r.schema = s
+	r.schemaHeader = s != nil && s.ColumnNames != nil
+	r.fieldNames = nil
+	if s == nil {
+		return
+	}
+	if s.ColumnNames != nil {
+		r.fieldNames = s.ColumnNames
+	} else {
+		r.fieldNames = make([]string, len(s.Columns))
+		for i, cs := range s.Columns {
+			r.fieldNames[i] = cs.Name
+		}
+	}
+}
+
+// validate checks record, which starts on line recLine, against the
+// attached schema.
+func (r *Reader) validate(record []Column, recLine int) error {
+	s := r.schema
+	var errs []*ColumnError
+	if r.schemaHeader {
+		r.schemaHeader = false
+		for i, name := range s.ColumnNames {
+			var col Column
+			if i < len(record) {
+				col = record[i]
+			}
+			if col.IsNull || col.Value != name {
+				errs = append(errs, &ColumnError{Index: i, Name: name, Value: col.Value, Err: ErrColumnName})
+			}
+		}
+		for i := len(s.ColumnNames); i < len(record); i++ {
+			errs = append(errs, &ColumnError{Index: i, Value: record[i].Value, Err: ErrColumnName})
+		}
+	} else {
+		for i := range s.Columns {
+			var col Column
+			if i < len(record) {
+				col = record[i]
+			} else if s.StrictColumnCount {
+				errs = append(errs, &ColumnError{Index: i, Name: s.Columns[i].Name, Err: ErrColumnCount})
+				continue
+			}
+			if err := s.Columns[i].check(col); err != nil {
+				errs = append(errs, &ColumnError{Index: i, Name: s.Columns[i].Name, Value: col.Value, Err: err})
+			}
+		}
+		if s.StrictColumnCount {
+			for i := len(s.Columns); i < len(record); i++ {
+				errs = append(errs, &ColumnError{Index: i, Value: record[i].Value, Err: ErrColumnCount})
+			}
+		}
+	}
+	if errs == nil {
+		return nil
+	}
+	return &ValidationError{StartLine: recLine, Line: r.numLine, Columns: errs}
+}
+
+// check returns the first constraint of s violated by col, or nil.
+func (s *ColumnSchema) check(col Column) error {
+	if col.IsMissing() {
+		if s.Required {
+			return ErrRequired
+		}
+		return nil
+	}
+	if s.MaxLength > 0 && utf8.RuneCountInString(col.Value) > s.MaxLength {
+		return ErrTooLong
+	}
+	if s.Pattern != nil && !s.Pattern.MatchString(col.Value) {
+		return ErrPatternMismatch
+	}
+	if _, err := s.convert(col); err != nil {
+		return err
+	}
+	return nil
+}
+
+// convert converts the value of col to the Go type of the column.
+// NULL columns and unquoted empty columns convert to nil.
+func (s *ColumnSchema) convert(col Column) (interface{}, error) {
+	if col.IsNull || col.Value == "" && !col.Quoted {
+		return nil, nil
+	}
+	switch s.Type {
+	case TypeInt:
+		return col.AsInt64()
+	case TypeFloat:
+		return col.AsFloat64()
+	case TypeBool:
+		return col.AsBool()
+	case TypeTime:
+		layout := s.TimeLayout
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return col.AsTime(layout)
+	}
+	return col.Value, nil
+}
+
+// ReadCSVWithSchema reads all records from r and converts them according to
+// schema. Each record is returned as a map from column name to a value of
+// the column's Go type, or nil for NULL (unquoted empty) fields.
+// Fields are matched to the schema's columns by position, so the input
+// should not contain a header row. Every record must have exactly one field
+// per schema column. ReadCSVWithSchema returns an error if schema is nil or
+// has no columns.
+func ReadCSVWithSchema(r io.Reader, schema *Schema) ([]map[string]interface{}, error) {
+	if schema == nil || len(schema.Columns) == 0 {
+		return nil, errEmptySchema
+	}
+	cr := NewReader(r)
+	cr.FieldsPerRecord = len(schema.Columns)
+	cr.ReuseRecord = true
+	var out []map[string]interface{}
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := make(map[string]interface{}, len(record))
+		for i, col := range record {
+			if i >= len(schema.Columns) {
+				break
+			}
+			cs := &schema.Columns[i]
+			v, err := cs.convert(col)
+			if err != nil {
+				return nil, fmt.Errorf("csv: record %d: column %q: %w", len(out)+1, cs.Name, err)
+			}
+			row[cs.Name] = v
+		}
+		out = append(out, row)
+	}
+}
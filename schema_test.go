@@ -0,0 +1,217 @@
+package csv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSVWithSchema(t *testing.T) {
+	schema := &Schema{Columns: []ColumnSchema{
+		{Name: "id", Type: TypeInt},
+		{Name: "name", Type: TypeString},
+		{Name: "score", Type: TypeFloat},
+		{Name: "active", Type: TypeBool},
+		{Name: "joined", Type: TypeTime, TimeLayout: "2006-01-02"},
+	}}
+	in := `1,Rob,9.5,true,2009-11-10
+2,"",10,false,
+3,,,,2012-03-28
+`
+	out, err := ReadCSVWithSchema(strings.NewReader(in), schema)
+	if err != nil {
+		t.Fatalf("ReadCSVWithSchema() error: %v", err)
+	}
+	date := func(s string) time.Time {
+		d, _ := time.Parse("2006-01-02", s)
+		return d
+	}
+	want := []map[string]interface{}{
+		{"id": int64(1), "name": "Rob", "score": 9.5, "active": true, "joined": date("2009-11-10")},
+		{"id": int64(2), "name": "", "score": float64(10), "active": false, "joined": nil},
+		{"id": int64(3), "name": nil, "score": nil, "active": nil, "joined": date("2012-03-28")},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("ReadCSVWithSchema() =\n%v\nwant\n%v", out, want)
+	}
+	if _, ok := out[1]["score"].(float64); !ok {
+		t.Errorf("score is %T, want float64", out[1]["score"])
+	}
+
+	for _, s := range []*Schema{nil, {}} {
+		if out, err := ReadCSVWithSchema(strings.NewReader("a,b\n"), s); err != errEmptySchema || out != nil {
+			t.Errorf("ReadCSVWithSchema() with schema %v = %v, %v, want nil, %v", s, out, err, errEmptySchema)
+		}
+	}
+
+	_, err = ReadCSVWithSchema(strings.NewReader("1,x,1.5,maybe,\n"), schema)
+	if !errors.Is(err, strconv.ErrSyntax) || !strings.Contains(err.Error(), `"active"`) {
+		t.Errorf("ReadCSVWithSchema() with bad bool: error = %v", err)
+	}
+
+	_, err = ReadCSVWithSchema(strings.NewReader("1,x\n"), schema)
+	if !errors.Is(err, ErrFieldCount) {
+		t.Errorf("ReadCSVWithSchema() with short record: error = %v, want ErrFieldCount", err)
+	}
+}
+
+func TestSetSchema(t *testing.T) {
+	schema := &Schema{
+		ColumnNames: []string{"id", "email", "code"},
+		Columns: []ColumnSchema{
+			{Name: "id", Type: TypeInt, Required: true},
+			{Name: "email", Required: true, Pattern: regexp.MustCompile(`^[\w.]+@[\w.]+$`)},
+			{Name: "code", MaxLength: 3},
+		},
+	}
+	in := "id,email,code\n" +
+		"1,rob@example.com,abc\n" +
+		"2,ken@example.com,\n" +
+		"x,\"\",abcd\n" +
+		"4, \n" +
+		"5,\"multi\nline@example.com\",äöü,extra\n"
+	r := NewReader(strings.NewReader(in))
+	r.FieldsPerRecord = -1
+	r.SetSchema(schema)
+
+	type result struct {
+		line int
+		errs []error
+	}
+	var got []result
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if record == nil {
+			t.Fatalf("Read() returned no record, error: %v", err)
+		}
+		res := result{line: -1}
+		if err != nil {
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Read() error = %v, want ValidationError", err)
+			}
+			res.line = verr.StartLine
+			for _, ce := range verr.Columns {
+				res.errs = append(res.errs, ce.Err)
+			}
+		}
+		got = append(got, res)
+	}
+	want := []result{
+		{line: -1},
+		{line: -1},
+		{line: -1},
+		{line: 4, errs: []error{strconv.ErrSyntax, ErrRequired, ErrTooLong}},
+		{line: 5, errs: []error{ErrRequired}},
+		{line: 6, errs: []error{ErrPatternMismatch}},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d records, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i].line != want[i].line || len(got[i].errs) != len(want[i].errs) {
+			t.Errorf("record %d: got %v, want %v", i, got[i], want[i])
+			continue
+		}
+		for j, err := range got[i].errs {
+			if !errors.Is(err, want[i].errs[j]) {
+				t.Errorf("record %d, violation %d: got %v, want %v", i, j, err, want[i].errs[j])
+			}
+		}
+	}
+	if n := r.Metrics().RecordsRead; n != 6 {
+		t.Errorf("RecordsRead = %d, want 6", n)
+	}
+}
+
+func TestSchemaStrictColumnCount(t *testing.T) {
+	schema := &Schema{
+		Columns: []ColumnSchema{
+			{Name: "id", Required: true, Pattern: regexp.MustCompile(`^\d+$`)},
+			{Name: "date", Pattern: regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)},
+			{Name: "note", MaxLength: 4},
+		},
+		StrictColumnCount: true,
+	}
+	in := "1,2020-01-02,ok\n" +
+		"2,2020-01-02\n" +
+		"3,2020-01-02,ok,extra,more\n" +
+		",01/02/2020,too long,extra\n"
+	r := NewReader(strings.NewReader(in))
+	r.FieldsPerRecord = -1
+	r.SetSchema(schema)
+
+	want := [][]ColumnError{
+		nil,
+		{{Index: 2, Name: "note", Err: ErrColumnCount}},
+		{{Index: 3, Value: "extra", Err: ErrColumnCount}, {Index: 4, Value: "more", Err: ErrColumnCount}},
+		{
+			{Index: 0, Name: "id", Err: ErrRequired},
+			{Index: 1, Name: "date", Value: "01/02/2020", Err: ErrPatternMismatch},
+			{Index: 2, Name: "note", Value: "too long", Err: ErrTooLong},
+			{Index: 3, Value: "extra", Err: ErrColumnCount},
+		},
+	}
+	for i, w := range want {
+		_, err := r.Read()
+		var got []ColumnError
+		if err != nil {
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Read() error = %v, want ValidationError", err)
+			}
+			got = verr.Errors()
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("record %d: violations = %v, want %v", i, got, w)
+		}
+	}
+
+	// Without StrictColumnCount, missing fields are checked as empty and
+	// extra fields are ignored.
+	schema.StrictColumnCount = false
+	r = NewReader(strings.NewReader(in))
+	r.FieldsPerRecord = -1
+	r.SetSchema(schema)
+	for i := 0; i < 3; i++ {
+		if _, err := r.Read(); err != nil {
+			t.Errorf("record %d: Read() error: %v", i, err)
+		}
+	}
+}
+
+func TestSetSchemaHeader(t *testing.T) {
+	schema := &Schema{ColumnNames: []string{"a", "b"}}
+	r := NewReader(strings.NewReader("a,c,d\n1,2,3\n"))
+	r.SetSchema(schema)
+	_, err := r.ReadAll()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("ReadAll() error = %v, want ValidationError", err)
+	}
+	if len(verr.Columns) != 2 || verr.Columns[0].Index != 1 || verr.Columns[1].Index != 2 {
+		t.Errorf("violations = %v, want columns 1 and 2", verr)
+	}
+	if !errors.Is(verr.Columns[0], ErrColumnName) {
+		t.Errorf("violation = %v, want %v", verr.Columns[0], ErrColumnName)
+	}
+	const msg = `csv: record on line 1: invalid fields: column 1 ("b"), value "c": unexpected column name; column 2 (""), value "d": unexpected column name`
+	if verr.Error() != msg {
+		t.Errorf("Error() = %q, want %q", verr.Error(), msg)
+	}
+
+	r = NewReader(strings.NewReader("a,c\n"))
+	r.SetSchema(schema)
+	r.SetSchema(nil)
+	if _, err := r.ReadAll(); err != nil {
+		t.Errorf("ReadAll() without schema: error = %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build go1.23
+// +build go1.23
+
+package csv
+
+import "iter"
+
+// WriteSeq writes the records produced by seq to w using Write and then
+// calls Flush, returning any error from the Flush. If Write fails, seq is
+// not advanced any further; the records written so far are still flushed
+// and the error from Write is returned.
+func (w *Writer) WriteSeq(seq iter.Seq[[]Column]) error {
+	var err error
+	for record := range seq {
+		if err = w.Write(record); err != nil {
+			break
+		}
+	}
+	if errFlush := w.flush(); err == nil {
+		err = errFlush
+	}
+	return err
+}
+
+// ColumnsFromSlice returns an iterator over rows, for use with WriteSeq.
+func ColumnsFromSlice(rows [][]Column) iter.Seq[[]Column] {
+	return func(yield func([]Column) bool) {
+		for _, row := range rows {
+			if !yield(row) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+//go:build go1.23
+// +build go1.23
+
+package csv
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteSeq(t *testing.T) {
+	rows := [][]Column{{c("a"), q("b")}, {c("c,d")}}
+	var b strings.Builder
+	w := NewWriter(&b)
+	if err := w.WriteSeq(ColumnsFromSlice(rows)); err != nil {
+		t.Fatalf("WriteSeq() error: %v", err)
+	}
+	if got, want := b.String(), "a,\"b\"\n\"c,d\"\n"; got != want {
+		t.Errorf("WriteSeq() = %q, want %q", got, want)
+	}
+}
+
+func TestWriteSeqError(t *testing.T) {
+	var b strings.Builder
+	w := NewWriter(&b)
+	w.QuoteStyle = QuoteNever
+	w.StrictQuoting = true
+	advanced := 0
+	seq := func(yield func([]Column) bool) {
+		for _, v := range []string{"ok", "needs,quotes", "never"} {
+			advanced++
+			if !yield([]Column{c(v)}) {
+				return
+			}
+		}
+	}
+	if err := w.WriteSeq(seq); !errors.Is(err, ErrNeedsQuoting) {
+		t.Errorf("WriteSeq() error = %v, want %v", err, ErrNeedsQuoting)
+	}
+	if advanced != 2 {
+		t.Errorf("iterator advanced %d times, want 2", advanced)
+	}
+	if got, want := b.String(), "ok\n"; !strings.HasPrefix(got, want) {
+		t.Errorf("WriteSeq() flushed %q, want prefix %q", got, want)
+	}
+
+	w = NewWriter(&chunkWriter{err: errors.New("boom")})
+	w.AutoFlush = true
+	advanced = 0
+	if err := w.WriteSeq(seq); err == nil || err.Error() != "boom" {
+		t.Errorf("WriteSeq() with failing writer error = %v, want boom", err)
+	}
+	if advanced != 1 {
+		t.Errorf("iterator advanced %d times with AutoFlush, want 1", advanced)
+	}
+}
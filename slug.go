@@ -0,0 +1,79 @@
+package csv
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// slugFrom and slugTo map lower case Latin letters to the ASCII letter
+// left over after NFKD decomposition and removal of non-ASCII runes.
+// slugFrom holds the letters; slugTo holds the corresponding base letters.
+const (
+	slugFrom = "àáâãäåçèéêëìíîïñòóôõöùúûüýÿāăąćĉ" +
+		"ċčďēĕėęěĝğġģĥĩīĭįĵķĺļľŀńņňŉōŏőŕŗ" +
+		"řśŝşšţťũūŭůűųŵŷźżžſơưǎǐǒǔǖǘǚǜǟǡǧ" +
+		"ǩǫǭǰǵǹǻȁȃȅȇȉȋȍȏȑȓȕȗșțȟȧȩȫȭȯȱȳḁḃḅ" +
+		"ḇḉḋḍḏḑḓḕḗḙḛḝḟḡḣḥḧḩḫḭḯḱḳḵḷḹḻḽḿṁṃṅ" +
+		"ṇṉṋṍṏṑṓṕṗṙṛṝṟṡṣṥṧṩṫṭṯṱṳṵṷṹṻṽṿẁẃẅ" +
+		"ẇẉẋẍẏẑẓẕẖẗẘẙẚẛạảấầẩẫậắằẳẵặẹẻẽếềể" +
+		"ễệỉịọỏốồổỗộớờởỡợụủứừửữựỳỵỷỹ"
+	slugTo = "aaaaaaceeeeiiiinooooouuuuyyaaacc" +
+		"ccdeeeeegggghiiiijkllllnnnnooorr" +
+		"rssssttuuuuuuwyzzzsouaiouuuuuaag" +
+		"koojgnaaaeeiioorruusthaeooooyabb" +
+		"bcdddddeeeeefghhhhhiikkkllllmmmn" +
+		"nnnoooopprrrrsssssttttuuuuuvvwww" +
+		"wwxxyzzzhtwyasaaaaaaaaaaaaeeeeee" +
+		"eeiioooooooooooouuuuuuuyyyy"
+)
+
+// slugFold maps lower case Latin letters to their ASCII base letters.
+var slugFold = func() map[rune]string {
+	m := map[rune]string{
+		'ĳ': "ij",
+		'ǆ': "dz",
+		'ǉ': "lj",
+		'ǌ': "nj",
+		'ǳ': "dz",
+	}
+	to := slugTo
+	for _, r := range slugFrom {
+		m[r] = to[:1]
+		to = to[1:]
+	}
+	return m
+}()
+
+// Slugify returns an unquoted column holding a URL-safe slug of c's value.
+// The value is lower cased and folded to ASCII as NFKD decomposition
+// followed by removal of non-ASCII runes would. Spaces and underscores
+// become hyphens, other punctuation is dropped, runs of hyphens are
+// collapsed and leading and trailing hyphens are removed.
+//
+//	Column{Value: "Grüße aus Köln!"}.Slugify() // Column{Value: "grue-aus-koln"}
+func (c Column) Slugify() Column {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(c.Value) {
+		var s string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			s = string(r)
+		case r == '-', r == '_', unicode.IsSpace(r):
+			hyphen = b.Len() > 0
+			continue
+		case r >= utf8.RuneSelf:
+			s = slugFold[r]
+		}
+		if s == "" {
+			continue
+		}
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(s)
+	}
+	return Column{Value: b.String()}
+}
@@ -0,0 +1,30 @@
+package csv
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Hello World", "hello-world"},
+		{"Über Straße", "uber-strae"},
+		{"Crème brûlée", "creme-brulee"},
+		{"Ærøskøbing", "rskbing"},
+		{"snake_case_name", "snake-case-name"},
+		{"Rock & Roll!", "rock-roll"},
+		{"a   b\t\tc", "a-b-c"},
+		{"--leading and trailing--", "leading-and-trailing"},
+		{"  spaced  ", "spaced"},
+		{"a - b", "a-b"},
+		{"Việt Nam", "viet-nam"},
+		{"ĳssel", "ijssel"},
+		{"日本語", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		got := Column{Value: tt.in, Quoted: true}.Slugify()
+		if got != (Column{Value: tt.want}) {
+			t.Errorf("Slugify(%q) = %+v, want %q unquoted", tt.in, got, tt.want)
+		}
+	}
+}
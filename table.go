@@ -0,0 +1,827 @@
+package csv
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// These are the errors returned by the methods of Table.
+var (
+	ErrUnknownColumn   = errors.New("unknown column")
+	ErrDuplicateColumn = errors.New("duplicate column")
+	ErrRowRange        = errors.New("row index out of range")
+	ErrColumnLength    = errors.New("column length does not match row count")
+	ErrJSONShape       = errors.New("JSON input is not an array of objects")
+	ErrDuplicateEntry  = errors.New("duplicate entry")
+	ErrSchemaMismatch  = errors.New("headers differ")
+)
+
+// A Table is a dataset of records with named columns, such as a CSV file
+// with a header row.
+//
+// Rows may have fewer fields than there are Headers. Missing fields read
+// as the zero Column and are added by Set and AddColumn as needed.
+type Table struct {
+	Headers []string
+	Rows    [][]Column
+
+	// If PreserveQuoted is true, ToJSON writes each field as an object
+	// holding its value and whether it is quoted.
+	PreserveQuoted bool
+
+	// PivotAggregator, if non-nil, is called by Pivot to combine the
+	// values of rows that fall into the same cell. It receives the value
+	// combined so far and the next one. If it is nil, such rows make
+	// Pivot fail.
+	PivotAggregator func(existing, new Column) Column
+
+	// MeltOptions configures the columns added by Melt.
+	MeltOptions MeltOptions
+
+	// indices holds the indexes built by BuildIndex, by column name.
+	indices map[string]map[string][]int
+}
+
+// NewTable returns a Table with the given column names and rows.
+// The slices are used as they are, not copied.
+func NewTable(headers []string, rows [][]Column) *Table {
+	return &Table{Headers: headers, Rows: rows}
+}
+
+// index returns the index of the column called name.
+func (t *Table) index(name string) (int, error) {
+	for i, h := range t.Headers {
+		if h == name {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("csv: %w %q", ErrUnknownColumn, name)
+}
+
+// checkRow returns an error if row is not a valid index into t.Rows.
+func (t *Table) checkRow(row int) error {
+	if row < 0 || row >= len(t.Rows) {
+		return fmt.Errorf("csv: %w: %d", ErrRowRange, row)
+	}
+	return nil
+}
+
+// Get returns the field in column col of the row'th row.
+func (t *Table) Get(row int, col string) (Column, error) {
+	if err := t.checkRow(row); err != nil {
+		return Column{}, err
+	}
+	i, err := t.index(col)
+	if err != nil {
+		return Column{}, err
+	}
+	if i >= len(t.Rows[row]) {
+		return Column{}, nil
+	}
+	return t.Rows[row][i], nil
+}
+
+// Set replaces the field in column col of the row'th row with val.
+func (t *Table) Set(row int, col string, val Column) error {
+	if err := t.checkRow(row); err != nil {
+		return err
+	}
+	i, err := t.index(col)
+	if err != nil {
+		return err
+	}
+	for len(t.Rows[row]) <= i {
+		t.Rows[row] = append(t.Rows[row], Column{})
+	}
+	t.Rows[row][i] = val
+	t.InvalidateIndex(col)
+	return nil
+}
+
+// Column returns a newly allocated slice holding the fields of the
+// column called name, one per row.
+func (t *Table) Column(name string) ([]Column, error) {
+	i, err := t.index(name)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]Column, len(t.Rows))
+	for r, record := range t.Rows {
+		if i < len(record) {
+			values[r] = record[i]
+		}
+	}
+	return values, nil
+}
+
+// AddColumn appends a column called name holding values, which must have
+// one field per row. If values is nil, the new column is empty.
+func (t *Table) AddColumn(name string, values []Column) error {
+	if _, err := t.index(name); err == nil {
+		return fmt.Errorf("csv: %w %q", ErrDuplicateColumn, name)
+	}
+	if values != nil && len(values) != len(t.Rows) {
+		return fmt.Errorf("csv: %w: got %d fields for %d rows", ErrColumnLength, len(values), len(t.Rows))
+	}
+	i := len(t.Headers)
+	t.Headers = append(t.Headers, name)
+	for r := range t.Rows {
+		for len(t.Rows[r]) < i {
+			t.Rows[r] = append(t.Rows[r], Column{})
+		}
+		var val Column
+		if values != nil {
+			val = values[r]
+		}
+		t.Rows[r] = append(t.Rows[r][:i], val)
+	}
+	return nil
+}
+
+// AddColumnWithDefault appends a column called name holding defaultValue
+// in every row.
+func (t *Table) AddColumnWithDefault(name, defaultValue string) error {
+	values := make([]Column, len(t.Rows))
+	for i := range values {
+		values[i] = Column{Value: defaultValue}
+	}
+	return t.AddColumn(name, values)
+}
+
+// AddRow appends a row holding values, one per column. It returns an
+// error wrapping ErrFieldCount if the number of values differs from the
+// number of columns.
+func (t *Table) AddRow(values ...string) error {
+	if len(values) != len(t.Headers) {
+		return fmt.Errorf("csv: %w: got %d values for %d columns", ErrFieldCount, len(values), len(t.Headers))
+	}
+	row := make([]Column, len(values))
+	for i, v := range values {
+		row[i] = Column{Value: v}
+	}
+	t.Rows = append(t.Rows, row)
+	t.InvalidateAllIndexes()
+	return nil
+}
+
+// AddRowMap appends a row taking the field of each column from m by name.
+// Columns missing from m are left empty. If m has a key that is not a
+// column, AddRowMap returns an error wrapping ErrUnknownColumn and adds
+// no row.
+func (t *Table) AddRowMap(m map[string]string) error {
+	row := make([]Column, len(t.Headers))
+	n := 0
+	for i, h := range t.Headers {
+		if v, ok := m[h]; ok {
+			row[i] = Column{Value: v}
+			n++
+		}
+	}
+	if n < len(m) {
+		var unknown []string
+		for k := range m {
+			if _, err := t.index(k); err != nil {
+				unknown = append(unknown, k)
+			}
+		}
+		sort.Strings(unknown)
+		return fmt.Errorf("csv: %w %q", ErrUnknownColumn, unknown)
+	}
+	t.Rows = append(t.Rows, row)
+	t.InvalidateAllIndexes()
+	return nil
+}
+
+// DropColumn removes the column called name from the table.
+func (t *Table) DropColumn(name string) error {
+	i, err := t.index(name)
+	if err != nil {
+		return err
+	}
+	t.InvalidateIndex(name)
+	t.Headers = append(t.Headers[:i], t.Headers[i+1:]...)
+	for r, record := range t.Rows {
+		if i < len(record) {
+			t.Rows[r] = append(record[:i], record[i+1:]...)
+		}
+	}
+	return nil
+}
+
+// RenameColumn renames the column called old to new.
+func (t *Table) RenameColumn(old, new string) error {
+	i, err := t.index(old)
+	if err != nil {
+		return err
+	}
+	if j, err := t.index(new); err == nil && j != i {
+		return fmt.Errorf("csv: %w %q", ErrDuplicateColumn, new)
+	}
+	t.Headers[i] = new
+	if index, ok := t.indices[old]; ok {
+		delete(t.indices, old)
+		t.indices[new] = index
+	}
+	return nil
+}
+
+// RenameColumns returns a copy of t whose columns are renamed from the
+// keys of renames to the corresponding values, all at once, so that two
+// columns can swap names. Columns not in renames keep their names. If a
+// key is not a column of t, RenameColumns returns an error wrapping
+// ErrUnknownColumn; if the new names would hold a duplicate, it returns
+// a *DuplicateColumnError. The returned Table shares its rows with t.
+func (t *Table) RenameColumns(renames map[string]string) (Table, error) {
+	for old := range renames {
+		if _, err := t.index(old); err != nil {
+			return Table{}, err
+		}
+	}
+	out := *t
+	out.Headers = make([]string, len(t.Headers))
+	for i, h := range t.Headers {
+		if name, ok := renames[h]; ok {
+			h = name
+		}
+		out.Headers[i] = h
+	}
+	if err := uniqueHeaders(out.Headers, false); err != nil {
+		return Table{}, err
+	}
+	out.Rows = t.Rows[:len(t.Rows):len(t.Rows)]
+	out.indices = nil
+	return out, nil
+}
+
+// NormalizeHeaders returns a copy of t whose column names are lower cased,
+// with each run of characters other than Unicode letters and digits
+// replaced by an underscore, and leading and trailing underscores
+// removed, so that "Unit Price ($)" becomes "unit_price". Names that
+// become equal are told apart as by Reader.AllowDuplicateHeaders, by
+// appending "_2", "_3", and so on. The returned Table shares its rows
+// with t.
+func (t *Table) NormalizeHeaders() Table {
+	out := *t
+	out.Headers = make([]string, len(t.Headers))
+	for i, h := range t.Headers {
+		out.Headers[i] = normalizeHeader(h)
+	}
+	uniqueHeaders(out.Headers, true)
+	out.Rows = t.Rows[:len(t.Rows):len(t.Rows)]
+	out.indices = nil
+	return out
+}
+
+func normalizeHeader(name string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			underscore = b.Len() > 0
+			continue
+		}
+		if underscore {
+			b.WriteByte('_')
+			underscore = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// rowMap returns a new map from column name to the field of record.
+func (t *Table) rowMap(record []Column) map[string]Column {
+	m := make(map[string]Column, len(t.Headers))
+	for i, h := range t.Headers {
+		if i < len(record) {
+			m[h] = record[i]
+		} else {
+			m[h] = Column{}
+		}
+	}
+	return m
+}
+
+// Filter returns a Table holding the rows for which keep returns true.
+// keep receives each row as a newly allocated map from column name to
+// field. The returned Table has the same Headers as t, and its rows share
+// their fields with t.
+func (t *Table) Filter(keep func(row map[string]Column) bool) Table {
+	out, _ := t.Partition(keep)
+	return out
+}
+
+// Reject is like Filter but returns the rows for which drop returns false.
+func (t *Table) Reject(drop func(row map[string]Column) bool) Table {
+	_, out := t.Partition(drop)
+	return out
+}
+
+// Partition calls pred once for each row like Filter and returns a Table
+// holding the rows for which it returns true and one holding the others.
+func (t *Table) Partition(pred func(row map[string]Column) bool) (Table, Table) {
+	in := Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+	out := Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+	for _, record := range t.Rows {
+		if pred(t.rowMap(record)) {
+			in.Rows = append(in.Rows, record)
+		} else {
+			out.Rows = append(out.Rows, record)
+		}
+	}
+	return in, out
+}
+
+// A SortKey names a column to sort a Table by and how to compare it.
+type SortKey struct {
+	Column          string // Name of the column
+	Descending      bool   // True to sort from the largest value down
+	CaseInsensitive bool   // True to compare values ignoring case
+}
+
+// Sort returns a Table holding the rows of t ordered by the columns given
+// in by, in priority order. Fields are compared with Column.Compare, and
+// missing fields compare as the zero Column. The sort is stable, so rows
+// that compare equal keep their order. If a column in by does not exist,
+// Sort returns a zero Table and an error wrapping ErrUnknownColumn.
+// The rows of the returned Table share their fields with t.
+func (t *Table) Sort(by []SortKey) (Table, error) {
+	idx := make([]int, len(by))
+	for k, key := range by {
+		i, err := t.index(key.Column)
+		if err != nil {
+			return Table{}, err
+		}
+		idx[k] = i
+	}
+	out := Table{
+		Headers:        append([]string(nil), t.Headers...),
+		Rows:           append([][]Column(nil), t.Rows...),
+		PreserveQuoted: t.PreserveQuoted,
+	}
+	field := func(record []Column, i int, fold bool) Column {
+		var col Column
+		if i < len(record) {
+			col = record[i]
+		}
+		if fold {
+			col.Value = strings.ToLower(col.Value)
+		}
+		return col
+	}
+	sort.SliceStable(out.Rows, func(a, b int) bool {
+		for k, key := range by {
+			n := field(out.Rows[a], idx[k], key.CaseInsensitive).Compare(field(out.Rows[b], idx[k], key.CaseInsensitive))
+			if key.Descending {
+				n = -n
+			}
+			if n != 0 {
+				return n < 0
+			}
+		}
+		return false
+	})
+	return out, nil
+}
+
+// Pivot reshapes t from long to wide format. The returned Table has a
+// column named indexCol followed by one column per distinct value of
+// columnCol, in order of first appearance, and one row per distinct value
+// of indexCol, also in order of first appearance. The cell for an index
+// value and a column value holds the valueCol field of the row having
+// both, or the zero Column if there is none. Fields are told apart by
+// their Value only, and missing fields read as empty values.
+//
+// If several rows share both values, their fields are combined with
+// t.PivotAggregator, or Pivot returns an error wrapping ErrDuplicateEntry
+// if it is nil. If a value of columnCol equals indexCol, Pivot returns
+// an error wrapping ErrDuplicateColumn.
+func (t *Table) Pivot(indexCol, columnCol, valueCol string) (Table, error) {
+	var idx [3]int
+	for k, name := range []string{indexCol, columnCol, valueCol} {
+		i, err := t.index(name)
+		if err != nil {
+			return Table{}, err
+		}
+		idx[k] = i
+	}
+	field := func(record []Column, i int) Column {
+		if i < len(record) {
+			return record[i]
+		}
+		return Column{}
+	}
+
+	// Find the distinct values of the index and the pivoted column.
+	out := Table{Headers: []string{indexCol}, PreserveQuoted: t.PreserveQuoted}
+	rowOf := make(map[string]int)
+	colOf := map[string]int{indexCol: 0}
+	var keys []Column
+	for _, record := range t.Rows {
+		key, col := field(record, idx[0]), field(record, idx[1])
+		if _, ok := rowOf[key.Value]; !ok {
+			rowOf[key.Value] = len(keys)
+			keys = append(keys, key)
+		}
+		if _, ok := colOf[col.Value]; !ok {
+			colOf[col.Value] = len(out.Headers)
+			out.Headers = append(out.Headers, col.Value)
+		} else if col.Value == indexCol {
+			return Table{}, fmt.Errorf("csv: %w %q", ErrDuplicateColumn, indexCol)
+		}
+	}
+
+	out.Rows = make([][]Column, len(keys))
+	filled := make([][]bool, len(keys))
+	for r, key := range keys {
+		out.Rows[r] = make([]Column, len(out.Headers))
+		out.Rows[r][0] = key
+		filled[r] = make([]bool, len(out.Headers))
+	}
+	for _, record := range t.Rows {
+		r := rowOf[field(record, idx[0]).Value]
+		c := colOf[field(record, idx[1]).Value]
+		val := field(record, idx[2])
+		if filled[r][c] {
+			if t.PivotAggregator == nil {
+				return Table{}, fmt.Errorf("csv: %w for %s %q and %s %q", ErrDuplicateEntry,
+					indexCol, keys[r].Value, columnCol, out.Headers[c])
+			}
+			val = t.PivotAggregator(out.Rows[r][c], val)
+		}
+		out.Rows[r][c] = val
+		filled[r][c] = true
+	}
+	return out, nil
+}
+
+// MeltOptions names the columns that Melt adds. Empty names default to
+// "variable" and "value".
+type MeltOptions struct {
+	VariableName string // Column holding the name of the melted column
+	ValueName    string // Column holding its value
+}
+
+// Melt reshapes t from wide to long format, the inverse of Pivot. The
+// returned Table has the columns idCols followed by a variable and a value
+// column named by t.MeltOptions, and for each row of t and each of
+// valueCols in turn, a row holding the idCols fields of the row, the name
+// of the value column and its field. Other columns are dropped. Missing
+// fields read as zero Columns.
+//
+// If idCols is empty, all columns not in valueCols are used as id
+// columns; if valueCols is empty, all columns not in idCols are melted.
+// Melt returns an error wrapping ErrUnknownColumn if a column does not
+// exist, or ErrDuplicateColumn if the variable or value column name is
+// already an id column.
+func (t *Table) Melt(idCols, valueCols []string) (Table, error) {
+	opts := t.MeltOptions
+	if opts.VariableName == "" {
+		opts.VariableName = "variable"
+	}
+	if opts.ValueName == "" {
+		opts.ValueName = "value"
+	}
+	indexes := func(names []string) ([]int, error) {
+		idx := make([]int, len(names))
+		for k, name := range names {
+			i, err := t.index(name)
+			if err != nil {
+				return nil, err
+			}
+			idx[k] = i
+		}
+		return idx, nil
+	}
+	idIdx, err := indexes(idCols)
+	if err != nil {
+		return Table{}, err
+	}
+	valIdx, err := indexes(valueCols)
+	if err != nil {
+		return Table{}, err
+	}
+	// others returns the indexes of the columns not in idx.
+	others := func(idx []int) (names []string, rest []int) {
+		used := make(map[int]bool, len(idx))
+		for _, i := range idx {
+			used[i] = true
+		}
+		for i, h := range t.Headers {
+			if !used[i] {
+				names, rest = append(names, h), append(rest, i)
+			}
+		}
+		return names, rest
+	}
+	if len(idCols) == 0 {
+		idCols, idIdx = others(valIdx)
+	} else if len(valueCols) == 0 {
+		valueCols, valIdx = others(idIdx)
+	}
+
+	out := Table{PreserveQuoted: t.PreserveQuoted}
+	out.Headers = append(append(out.Headers, idCols...), opts.VariableName, opts.ValueName)
+	for _, name := range []string{opts.VariableName, opts.ValueName} {
+		for _, h := range idCols {
+			if h == name {
+				return Table{}, fmt.Errorf("csv: %w %q", ErrDuplicateColumn, name)
+			}
+		}
+	}
+	if opts.VariableName == opts.ValueName {
+		return Table{}, fmt.Errorf("csv: %w %q", ErrDuplicateColumn, opts.ValueName)
+	}
+	field := func(record []Column, i int) Column {
+		if i < len(record) {
+			return record[i]
+		}
+		return Column{}
+	}
+	out.Rows = make([][]Column, 0, len(t.Rows)*len(valIdx))
+	for _, record := range t.Rows {
+		for k, vi := range valIdx {
+			row := make([]Column, len(idIdx), len(idIdx)+2)
+			for j, i := range idIdx {
+				row[j] = field(record, i)
+			}
+			row = append(row, Column{Value: valueCols[k]}, field(record, vi))
+			out.Rows = append(out.Rows, row)
+		}
+	}
+	return out, nil
+}
+
+// InferTypes returns the narrowest ColumnType that every value of each
+// column of t converts to, trying TypeInt, TypeFloat and TypeBool in turn
+// before falling back to TypeString. NULL and missing fields are ignored;
+// a column without any other values is TypeString.
+func (t *Table) InferTypes() []ColumnType {
+	types := make([]ColumnType, len(t.Headers))
+	for i := range t.Headers {
+		types[i] = TypeString
+	candidates:
+		for _, typ := range []ColumnType{TypeInt, TypeFloat, TypeBool} {
+			cs := ColumnSchema{Type: typ}
+			found := false
+			for _, record := range t.Rows {
+				if i >= len(record) || record[i].IsMissing() {
+					continue
+				}
+				if _, err := cs.convert(record[i]); err != nil {
+					continue candidates
+				}
+				found = true
+			}
+			if found {
+				types[i] = typ
+				break
+			}
+		}
+	}
+	return types
+}
+
+// Head returns a Table holding the first n rows of t, or all of them if
+// t has fewer than n rows.
+func (t *Table) Head(n int) Table {
+	if n > len(t.Rows) {
+		n = len(t.Rows)
+	}
+	return t.Slice(0, n)
+}
+
+// Tail returns a Table holding the last n rows of t, or all of them if
+// t has fewer than n rows.
+func (t *Table) Tail(n int) Table {
+	if n > len(t.Rows) {
+		n = len(t.Rows)
+	}
+	return t.Slice(len(t.Rows)-n, len(t.Rows))
+}
+
+// Slice returns a Table holding the rows of t from index start up to but
+// not including end. If the bounds are out of range, the returned Table
+// has no rows. Like the Tables returned by Head and Tail, it has the same
+// Headers as t, and its rows share their fields with t.
+func (t *Table) Slice(start, end int) Table {
+	out := Table{Headers: t.Headers, PreserveQuoted: t.PreserveQuoted}
+	if start < 0 || end > len(t.Rows) || start >= end {
+		return out
+	}
+	out.Rows = t.Rows[start:end:end]
+	return out
+}
+
+// Merge returns a Table holding the rows of t followed by those of other.
+// If the Headers of the tables differ in names or order, Merge returns an
+// error wrapping ErrSchemaMismatch. The returned Table has its own Headers
+// and Rows slices, but its rows share their fields with t and other.
+func (t *Table) Merge(other Table) (Table, error) {
+	if len(t.Headers) != len(other.Headers) {
+		return Table{}, fmt.Errorf("csv: %w: %q and %q", ErrSchemaMismatch, t.Headers, other.Headers)
+	}
+	for i, h := range t.Headers {
+		if other.Headers[i] != h {
+			return Table{}, fmt.Errorf("csv: %w: %q and %q", ErrSchemaMismatch, t.Headers, other.Headers)
+		}
+	}
+	out := Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+	if n := len(t.Rows) + len(other.Rows); n > 0 {
+		out.Rows = append(append(make([][]Column, 0, n), t.Rows...), other.Rows...)
+	}
+	return out, nil
+}
+
+// MergeRelaxed is like Merge but accepts tables with different columns.
+// The returned Table has the Headers of t followed by those of other that
+// t lacks, and each row has a field for every column. Fields of columns
+// missing from a row's table are unquoted and hold missingValue; missing
+// fields of short rows are zero Columns. The rows are newly allocated.
+// If either table has a repeated column name, which would make matching
+// columns ambiguous, MergeRelaxed returns a *DuplicateColumnError.
+func (t *Table) MergeRelaxed(other Table, missingValue string) (Table, error) {
+	for _, headers := range [][]string{t.Headers, other.Headers} {
+		if err := uniqueHeaders(headers, false); err != nil {
+			return Table{}, err
+		}
+	}
+	out := Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+	pos := make(map[string]int, len(t.Headers)+len(other.Headers))
+	for i, h := range t.Headers {
+		pos[h] = i
+	}
+	for _, h := range other.Headers {
+		if _, ok := pos[h]; !ok {
+			pos[h] = len(out.Headers)
+			out.Headers = append(out.Headers, h)
+		}
+	}
+	if n := len(t.Rows) + len(other.Rows); n > 0 {
+		out.Rows = make([][]Column, 0, n)
+	}
+	for _, tbl := range []*Table{t, &other} {
+		for _, record := range tbl.Rows {
+			row := make([]Column, len(out.Headers))
+			for i := range row {
+				row[i].Value = missingValue
+			}
+			for i, h := range tbl.Headers {
+				var col Column
+				if i < len(record) {
+					col = record[i]
+				}
+				row[pos[h]] = col
+			}
+			out.Rows = append(out.Rows, row)
+		}
+	}
+	return out, nil
+}
+
+// DropDuplicates returns a Table holding the first row of t for each
+// distinct combination of values in the columns keyCols, in order. If
+// keyCols is empty, all columns are used, so only identical rows are
+// dropped. Fields are told apart by their Value only, and missing fields
+// read as empty values. If a column does not exist, DropDuplicates
+// returns an error wrapping ErrUnknownColumn. The returned Table has the
+// same Headers as t, and its rows share their fields with t.
+func (t *Table) DropDuplicates(keyCols []string) (Table, error) {
+	key, err := t.rowKey(keyCols)
+	if err != nil {
+		return Table{}, err
+	}
+	out := Table{Headers: append([]string(nil), t.Headers...), PreserveQuoted: t.PreserveQuoted}
+	seen := make(map[string]bool)
+	for _, record := range t.Rows {
+		k := key(record)
+		if !seen[k] {
+			seen[k] = true
+			out.Rows = append(out.Rows, record)
+		}
+	}
+	return out, nil
+}
+
+// CountDuplicates returns the number of rows of t holding each distinct
+// combination of values in the columns keyCols, chosen as by
+// DropDuplicates. The map is keyed by the values as a Writer would write
+// them as a record, without quoting fields marked Quoted and without the
+// line terminator, such as `1,"a,b"` for the values 1 and a,b.
+func (t *Table) CountDuplicates(keyCols []string) (map[string]int, error) {
+	key, err := t.rowKey(keyCols)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, record := range t.Rows {
+		counts[key(record)]++
+	}
+	return counts, nil
+}
+
+// rowKey returns a function encoding the values of the columns keyCols,
+// or all columns if keyCols is empty, as described for CountDuplicates.
+func (t *Table) rowKey(keyCols []string) (func(record []Column) string, error) {
+	idx := make([]int, len(keyCols))
+	for k, name := range keyCols {
+		i, err := t.index(name)
+		if err != nil {
+			return nil, err
+		}
+		idx[k] = i
+	}
+	if len(keyCols) == 0 {
+		idx = make([]int, len(t.Headers))
+		for i := range idx {
+			idx[i] = i
+		}
+	}
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	return func(record []Column) string {
+		b.Reset()
+		for k, i := range idx {
+			if k > 0 {
+				w.w.WriteByte(',')
+			}
+			if i < len(record) {
+				w.writeField(Column{Value: record[i].Value}, &WriteMetrics{})
+			}
+		}
+		w.w.Flush()
+		return b.String()
+	}, nil
+}
+
+// WriteTo writes the table as CSV to w, starting with a header row, and
+// returns the number of bytes written. Rows are written as they are
+// encoded, not collected first. A table without headers is written
+// without a header row, so an empty table writes nothing.
+// It implements io.WriterTo.
+func (t *Table) WriteTo(w io.Writer) (int64, error) {
+	cw := NewWriter(w)
+	var err error
+	if len(t.Headers) > 0 {
+		header := make([]Column, len(t.Headers))
+		for i, h := range t.Headers {
+			header[i] = Column{Value: h}
+		}
+		err = cw.Write(header)
+	}
+	if err == nil {
+		err = cw.WriteAll(t.Rows)
+	}
+	return cw.Metrics().BytesWritten, err
+}
+
+// ReadFrom replaces the contents of the table with CSV data read from r
+// until EOF. The first record is the header row. Records may have
+// different numbers of fields. If a column name appears more than once in
+// the header row, ReadFrom returns a *DuplicateColumnError. ReadFrom
+// returns the number of bytes read from r. If an error occurs, the table
+// is left unchanged.
+// It implements io.ReaderFrom.
+func (t *Table) ReadFrom(r io.Reader) (int64, error) {
+	cr := &countingReader{r: r}
+	rd := NewReader(cr)
+	rd.FieldsPerRecord = -1
+	records, err := rd.ReadAll()
+	if err != nil {
+		return cr.n, err
+	}
+	var headers []string
+	if len(records) > 0 {
+		headers = make([]string, len(records[0]))
+		for i, col := range records[0] {
+			headers[i] = col.Value
+		}
+		if err := uniqueHeaders(headers, false); err != nil {
+			return cr.n, err
+		}
+		records = records[1:]
+	}
+	if len(records) == 0 {
+		records = nil
+	}
+	t.Headers, t.Rows = headers, records
+	t.InvalidateAllIndexes()
+	return cr.n, nil
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,737 @@
+package csv
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func newTestTable() *Table {
+	return NewTable([]string{"name", "lang"}, [][]Column{
+		{q("Rob Pike"), c("go")},
+		{c("Ken Thompson"), q("C")},
+		{c("Dennis Ritchie")},
+	})
+}
+
+func TestTableGetSet(t *testing.T) {
+	tbl := newTestTable()
+	if got, err := tbl.Get(1, "lang"); err != nil || got != q("C") {
+		t.Errorf("Get(1, lang) = %v, %v, want %v", got, err, q("C"))
+	}
+	if got, err := tbl.Get(2, "lang"); err != nil || got != (Column{}) {
+		t.Errorf("Get(2, lang) = %v, %v, want empty column", got, err)
+	}
+	if _, err := tbl.Get(3, "lang"); !errors.Is(err, ErrRowRange) {
+		t.Errorf("Get(3, lang) error = %v, want %v", err, ErrRowRange)
+	}
+	if _, err := tbl.Get(0, "year"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Get(0, year) error = %v, want %v", err, ErrUnknownColumn)
+	}
+
+	if err := tbl.Set(2, "lang", c("C")); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if want := []Column{c("Dennis Ritchie"), c("C")}; !reflect.DeepEqual(tbl.Rows[2], want) {
+		t.Errorf("row after Set() = %v, want %v", tbl.Rows[2], want)
+	}
+	if err := tbl.Set(-1, "lang", c("C")); !errors.Is(err, ErrRowRange) {
+		t.Errorf("Set(-1) error = %v, want %v", err, ErrRowRange)
+	}
+}
+
+func TestTableColumns(t *testing.T) {
+	tbl := newTestTable()
+	langs, err := tbl.Column("lang")
+	if err != nil {
+		t.Fatalf("Column() error: %v", err)
+	}
+	if want := []Column{c("go"), q("C"), {}}; !reflect.DeepEqual(langs, want) {
+		t.Errorf("Column(lang) = %v, want %v", langs, want)
+	}
+
+	if err := tbl.AddColumn("year", []Column{c("1956"), c("1943"), c("1941")}); err != nil {
+		t.Fatalf("AddColumn() error: %v", err)
+	}
+	if want := []Column{c("Dennis Ritchie"), {}, c("1941")}; !reflect.DeepEqual(tbl.Rows[2], want) {
+		t.Errorf("row after AddColumn() = %v, want %v", tbl.Rows[2], want)
+	}
+	if err := tbl.AddColumn("year", nil); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("AddColumn(year) again: error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	if err := tbl.AddColumn("x", []Column{c("1")}); !errors.Is(err, ErrColumnLength) {
+		t.Errorf("AddColumn() short: error = %v, want %v", err, ErrColumnLength)
+	}
+	if err := tbl.AddColumn("note", nil); err != nil {
+		t.Fatalf("AddColumn(nil) error: %v", err)
+	}
+
+	if err := tbl.RenameColumn("year", "born"); err != nil {
+		t.Fatalf("RenameColumn() error: %v", err)
+	}
+	if err := tbl.RenameColumn("born", "name"); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("RenameColumn() to existing: error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	if err := tbl.RenameColumn("year", "x"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("RenameColumn() unknown: error = %v, want %v", err, ErrUnknownColumn)
+	}
+
+	if err := tbl.DropColumn("lang"); err != nil {
+		t.Fatalf("DropColumn() error: %v", err)
+	}
+	if err := tbl.DropColumn("lang"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("DropColumn() again: error = %v, want %v", err, ErrUnknownColumn)
+	}
+	wantHeaders := []string{"name", "born", "note"}
+	if !reflect.DeepEqual(tbl.Headers, wantHeaders) {
+		t.Errorf("Headers = %q, want %q", tbl.Headers, wantHeaders)
+	}
+	wantRows := [][]Column{
+		{q("Rob Pike"), c("1956"), {}},
+		{c("Ken Thompson"), c("1943"), {}},
+		{c("Dennis Ritchie"), c("1941"), {}},
+	}
+	if !reflect.DeepEqual(tbl.Rows, wantRows) {
+		t.Errorf("Rows = %v, want %v", tbl.Rows, wantRows)
+	}
+}
+
+func TestTableRenameColumns(t *testing.T) {
+	tbl := NewTable([]string{"First Name", "Last Name", "Age"}, [][]Column{
+		{c("Ada"), c("Lovelace"), c("36")},
+	})
+	out, err := tbl.RenameColumns(map[string]string{"First Name": "first", "Age": "age"})
+	if err != nil {
+		t.Fatalf("RenameColumns() error: %v", err)
+	}
+	if got, want := out.Headers, []string{"first", "Last Name", "age"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameColumns() headers = %q, want %q", got, want)
+	}
+	if !reflect.DeepEqual(out.Rows, tbl.Rows) {
+		t.Errorf("RenameColumns() rows = %v, want %v", out.Rows, tbl.Rows)
+	}
+	if got, want := tbl.Headers, []string{"First Name", "Last Name", "Age"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameColumns() changed the original headers to %q", got)
+	}
+
+	out, err = tbl.RenameColumns(map[string]string{"First Name": "Last Name", "Last Name": "First Name"})
+	if err != nil {
+		t.Fatalf("RenameColumns() swapping names error: %v", err)
+	}
+	if got, want := out.Headers, []string{"Last Name", "First Name", "Age"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RenameColumns() swapping names headers = %q, want %q", got, want)
+	}
+
+	if _, err := tbl.RenameColumns(map[string]string{"Age": "Last Name"}); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("RenameColumns() to existing name error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	if _, err := tbl.RenameColumns(map[string]string{"Email": "email"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("RenameColumns() of unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+}
+
+func TestTableNormalizeHeaders(t *testing.T) {
+	tbl := NewTable([]string{"First Name", "Unit Price ($)", "Größe", "ÉTAT-Civil", "  id  ", "first_name", "№"}, nil)
+	out := tbl.NormalizeHeaders()
+	want := []string{"first_name", "unit_price", "größe", "état_civil", "id", "first_name_2", ""}
+	if !reflect.DeepEqual(out.Headers, want) {
+		t.Errorf("NormalizeHeaders() headers = %q, want %q", out.Headers, want)
+	}
+	if tbl.Headers[0] != "First Name" {
+		t.Errorf("NormalizeHeaders() changed the original headers to %q", tbl.Headers)
+	}
+}
+
+func TestTableAddRow(t *testing.T) {
+	tbl := NewTable([]string{"name", "lang"}, nil)
+	if err := tbl.AddRow("Rob Pike", "go"); err != nil {
+		t.Fatalf("AddRow() error: %v", err)
+	}
+	if err := tbl.AddRow("Ken Thompson"); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("AddRow() with too few values error = %v, want %v", err, ErrFieldCount)
+	}
+	if err := tbl.AddRowMap(map[string]string{"name": "Dennis Ritchie"}); err != nil {
+		t.Fatalf("AddRowMap() error: %v", err)
+	}
+	if err := tbl.AddRowMap(map[string]string{"name": "x", "year": "1", "age": "2"}); !errors.Is(err, ErrUnknownColumn) ||
+		!strings.Contains(err.Error(), `["age" "year"]`) {
+		t.Errorf("AddRowMap() with unknown keys error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if err := tbl.AddColumnWithDefault("year", "1970"); err != nil {
+		t.Fatalf("AddColumnWithDefault() error: %v", err)
+	}
+	if err := tbl.AddColumnWithDefault("lang", ""); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("AddColumnWithDefault() of existing column error = %v, want %v", err, ErrDuplicateColumn)
+	}
+	want := NewTable([]string{"name", "lang", "year"}, [][]Column{
+		{c("Rob Pike"), c("go"), c("1970")},
+		{c("Dennis Ritchie"), {}, c("1970")},
+	})
+	if !reflect.DeepEqual(tbl, want) {
+		t.Errorf("table = %v, want %v", tbl, want)
+	}
+	for i, row := range tbl.Rows {
+		if len(row) != len(tbl.Headers) {
+			t.Errorf("row %d has %d fields, want %d", i, len(row), len(tbl.Headers))
+		}
+	}
+}
+
+func TestTableFilter(t *testing.T) {
+	tbl := newTestTable()
+	out := tbl.Filter(func(row map[string]Column) bool {
+		return row["lang"].Value != "go"
+	})
+	if !reflect.DeepEqual(out.Headers, tbl.Headers) {
+		t.Errorf("Headers = %q, want %q", out.Headers, tbl.Headers)
+	}
+	if want := tbl.Rows[1:]; !reflect.DeepEqual(out.Rows, want) {
+		t.Errorf("Rows = %v, want %v", out.Rows, want)
+	}
+
+	// Each row gets its own map.
+	var maps []map[string]Column
+	tbl.Filter(func(row map[string]Column) bool {
+		maps = append(maps, row)
+		return false
+	})
+	if maps[0]["name"].Value != "Rob Pike" {
+		t.Errorf("first row map changed to %v", maps[0])
+	}
+
+	all := tbl.Filter(func(map[string]Column) bool { return true })
+	if !reflect.DeepEqual(all, *tbl) {
+		t.Errorf("Filter(true) = %v, want %v", all, *tbl)
+	}
+	if !reflect.DeepEqual(tbl, newTestTable()) {
+		t.Errorf("Filter() changed the table to %v", tbl)
+	}
+
+	empty := Table{Headers: []string{"a", "b"}}
+	if out := empty.Filter(func(map[string]Column) bool { return true }); !reflect.DeepEqual(out.Headers, empty.Headers) || len(out.Rows) != 0 {
+		t.Errorf("Filter() on empty table = %v", out)
+	}
+}
+
+func TestTablePartition(t *testing.T) {
+	tbl := newTestTable()
+	isGo := func(row map[string]Column) bool { return row["lang"].Value == "go" }
+	in, out := tbl.Partition(isGo)
+	if want := tbl.Rows[:1]; !reflect.DeepEqual(in.Rows, want) {
+		t.Errorf("Partition() first Rows = %v, want %v", in.Rows, want)
+	}
+	if want := tbl.Rows[1:]; !reflect.DeepEqual(out.Rows, want) {
+		t.Errorf("Partition() second Rows = %v, want %v", out.Rows, want)
+	}
+	if rej := tbl.Reject(isGo); !reflect.DeepEqual(rej, out) {
+		t.Errorf("Reject() = %v, want %v", rej, out)
+	}
+	if !reflect.DeepEqual(in.Headers, tbl.Headers) || !reflect.DeepEqual(out.Headers, tbl.Headers) {
+		t.Errorf("Partition() Headers = %q and %q, want %q", in.Headers, out.Headers, tbl.Headers)
+	}
+}
+
+func TestTableSort(t *testing.T) {
+	tbl := NewTable([]string{"last", "first", "salary"}, [][]Column{
+		{c("smith"), c("Anna"), c("3")},
+		{c("Jones"), c("Bob"), c("1")},
+		{c("Smith"), c("Anna"), c("5")},
+		{c("jones"), c("Bob"), c("2")},
+		{c("Smith"), c("Carl")},
+	})
+	orig := NewTable(append([]string(nil), tbl.Headers...), append([][]Column(nil), tbl.Rows...))
+
+	out, err := tbl.Sort([]SortKey{
+		{Column: "last", CaseInsensitive: true},
+		{Column: "first", Descending: true},
+	})
+	if err != nil {
+		t.Fatalf("Sort() error: %v", err)
+	}
+	// Equal rows keep their order: Jones before jones, smith before Smith.
+	want := [][]Column{tbl.Rows[1], tbl.Rows[3], tbl.Rows[4], tbl.Rows[0], tbl.Rows[2]}
+	if !reflect.DeepEqual(out.Rows, want) {
+		t.Errorf("Sort() = %v, want %v", out.Rows, want)
+	}
+	if !reflect.DeepEqual(out.Headers, tbl.Headers) {
+		t.Errorf("Sort() Headers = %q, want %q", out.Headers, tbl.Headers)
+	}
+	if !reflect.DeepEqual(tbl, orig) {
+		t.Errorf("Sort() changed the table to %v", tbl)
+	}
+
+	// Case-sensitive; the missing salary sorts last when descending.
+	out, _ = tbl.Sort([]SortKey{{Column: "last"}, {Column: "salary", Descending: true}})
+	want = [][]Column{tbl.Rows[1], tbl.Rows[2], tbl.Rows[4], tbl.Rows[3], tbl.Rows[0]}
+	if !reflect.DeepEqual(out.Rows, want) {
+		t.Errorf("Sort() = %v, want %v", out.Rows, want)
+	}
+
+	out, err = tbl.Sort([]SortKey{{Column: "last"}, {Column: "age"}})
+	if !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Sort() by unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if !reflect.DeepEqual(out, Table{}) {
+		t.Errorf("Sort() by unknown column = %v, want zero Table", out)
+	}
+}
+
+func TestTablePivot(t *testing.T) {
+	tbl := NewTable([]string{"date", "metric", "value"}, [][]Column{
+		{c("mon"), c("clicks"), c("10")},
+		{c("mon"), c("views"), c("100")},
+		{c("tue"), c("views"), c("120")},
+		{c("wed"), c("clicks"), c("7")},
+		{c("tue"), c("errors")},
+	})
+	out, err := tbl.Pivot("date", "metric", "value")
+	if err != nil {
+		t.Fatalf("Pivot() error: %v", err)
+	}
+	want := Table{
+		Headers: []string{"date", "clicks", "views", "errors"},
+		Rows: [][]Column{
+			{c("mon"), c("10"), c("100"), {}},
+			{c("tue"), {}, c("120"), {}},
+			{c("wed"), c("7"), {}, {}},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Pivot() =\n%v\nwant\n%v", out, want)
+	}
+
+	tbl.Rows = append(tbl.Rows, []Column{c("mon"), c("clicks"), c("5")})
+	if _, err := tbl.Pivot("date", "metric", "value"); !errors.Is(err, ErrDuplicateEntry) {
+		t.Errorf("Pivot() with duplicate error = %v, want %v", err, ErrDuplicateEntry)
+	}
+	tbl.PivotAggregator = func(existing, new Column) Column {
+		a, _ := strconv.Atoi(existing.Value)
+		b, _ := strconv.Atoi(new.Value)
+		return c(strconv.Itoa(a + b))
+	}
+	out, err = tbl.Pivot("date", "metric", "value")
+	if err != nil {
+		t.Fatalf("Pivot() with aggregator error: %v", err)
+	}
+	if got := out.Rows[0][1]; !reflect.DeepEqual(got, c("15")) {
+		t.Errorf("aggregated cell = %v, want 15", got)
+	}
+
+	if _, err := tbl.Pivot("date", "metric", "count"); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Pivot() by unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	tbl.Rows = append(tbl.Rows, []Column{c("thu"), c("date"), c("1")})
+	if _, err := tbl.Pivot("date", "metric", "value"); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("Pivot() creating the index column error = %v, want %v", err, ErrDuplicateColumn)
+	}
+}
+
+func TestTableMelt(t *testing.T) {
+	tbl := NewTable([]string{"date", "jan_sales", "feb_sales", "mar_sales", "note"}, [][]Column{
+		{c("2021"), c("1"), c("2"), c("3"), c("x")},
+		{c("2022"), c("4"), c("5")},
+	})
+	valueCols := []string{"jan_sales", "feb_sales", "mar_sales"}
+	out, err := tbl.Melt([]string{"date"}, valueCols)
+	if err != nil {
+		t.Fatalf("Melt() error: %v", err)
+	}
+	if got, want := len(out.Rows), len(tbl.Rows)*len(valueCols); got != want {
+		t.Fatalf("Melt() returned %d rows, want %d", got, want)
+	}
+	want := Table{
+		Headers: []string{"date", "variable", "value"},
+		Rows: [][]Column{
+			{c("2021"), c("jan_sales"), c("1")},
+			{c("2021"), c("feb_sales"), c("2")},
+			{c("2021"), c("mar_sales"), c("3")},
+			{c("2022"), c("jan_sales"), c("4")},
+			{c("2022"), c("feb_sales"), c("5")},
+			{c("2022"), c("mar_sales"), {}},
+		},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Melt() =\n%v\nwant\n%v", out, want)
+	}
+
+	// Melting the result of Pivot restores the long format.
+	pivoted, err := out.Pivot("date", "variable", "value")
+	if err != nil {
+		t.Fatalf("Pivot() error: %v", err)
+	}
+	back, err := pivoted.Melt([]string{"date"}, nil)
+	if err != nil {
+		t.Fatalf("Melt() of Pivot() error: %v", err)
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Errorf("Melt() of Pivot() =\n%v\nwant\n%v", back, want)
+	}
+
+	tbl.MeltOptions = MeltOptions{VariableName: "month", ValueName: "sales"}
+	out, err = tbl.Melt(nil, valueCols)
+	if err != nil {
+		t.Fatalf("Melt() without idCols error: %v", err)
+	}
+	if got, want := out.Headers, []string{"date", "note", "month", "sales"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Melt() without idCols headers = %q, want %q", got, want)
+	}
+	if got, want := len(out.Rows), len(tbl.Rows)*len(valueCols); got != want {
+		t.Errorf("Melt() without idCols returned %d rows, want %d", got, want)
+	}
+
+	if _, err := tbl.Melt([]string{"date"}, []string{"apr_sales"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Melt() of unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if _, err := tbl.Melt([]string{"dat"}, valueCols); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("Melt() by unknown id column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	tbl.MeltOptions.VariableName = "date"
+	if _, err := tbl.Melt([]string{"date"}, valueCols); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("Melt() with variable column named like id column error = %v, want %v", err, ErrDuplicateColumn)
+	}
+}
+
+func TestTableInferTypes(t *testing.T) {
+	tbl := NewTable([]string{"int", "float", "bool", "string", "empty", "mixed"}, [][]Column{
+		{c("1"), c("1.5"), c("true"), c("x"), c(""), c("1")},
+		{c("-2"), c("2"), c("no"), c("3"), {IsNull: true}, c("x")},
+		{{IsNull: true}, c(" "), c("1"), c("true")},
+	})
+	want := []ColumnType{TypeInt, TypeFloat, TypeBool, TypeString, TypeString, TypeString}
+	if got := tbl.InferTypes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("InferTypes() = %v, want %v", got, want)
+	}
+}
+
+func TestTableMerge(t *testing.T) {
+	a := NewTable([]string{"id", "name"}, [][]Column{{c("1"), c("Ann")}, {c("2"), c("Bob")}})
+	b := NewTable([]string{"id", "name"}, [][]Column{{c("3"), c("Cid")}})
+	out, err := a.Merge(*b)
+	if err != nil {
+		t.Fatalf("Merge() error: %v", err)
+	}
+	want := Table{
+		Headers: []string{"id", "name"},
+		Rows:    [][]Column{{c("1"), c("Ann")}, {c("2"), c("Bob")}, {c("3"), c("Cid")}},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("Merge() =\n%v\nwant\n%v", out, want)
+	}
+	if len(a.Rows) != 2 || len(b.Rows) != 1 {
+		t.Errorf("Merge() changed its inputs to %d and %d rows", len(a.Rows), len(b.Rows))
+	}
+
+	reordered := NewTable([]string{"name", "id"}, [][]Column{{c("Cid"), c("3")}})
+	if _, err := a.Merge(*reordered); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Merge() with reordered headers error = %v, want %v", err, ErrSchemaMismatch)
+	}
+	if _, err := a.Merge(Table{Headers: []string{"id"}}); !errors.Is(err, ErrSchemaMismatch) {
+		t.Errorf("Merge() with fewer headers error = %v, want %v", err, ErrSchemaMismatch)
+	}
+}
+
+func TestTableMergeRelaxed(t *testing.T) {
+	full := NewTable([]string{"id", "name", "city"}, [][]Column{{c("1"), c("Ann"), c("Oslo")}})
+	tests := []struct {
+		Name  string
+		T     *Table
+		Other *Table
+		Want  Table
+	}{{
+		Name:  "Identical",
+		T:     full,
+		Other: NewTable([]string{"id", "name", "city"}, [][]Column{{c("2"), c("Bob"), q("Rome")}}),
+		Want: Table{
+			Headers: []string{"id", "name", "city"},
+			Rows:    [][]Column{{c("1"), c("Ann"), c("Oslo")}, {c("2"), c("Bob"), q("Rome")}},
+		},
+	}, {
+		Name:  "Subset",
+		T:     full,
+		Other: NewTable([]string{"name", "id"}, [][]Column{{c("Bob"), c("2")}}),
+		Want: Table{
+			Headers: []string{"id", "name", "city"},
+			Rows:    [][]Column{{c("1"), c("Ann"), c("Oslo")}, {c("2"), c("Bob"), c("NA")}},
+		},
+	}, {
+		Name:  "Superset",
+		T:     NewTable([]string{"id"}, [][]Column{{c("2")}, {}}),
+		Other: full,
+		Want: Table{
+			Headers: []string{"id", "name", "city"},
+			Rows:    [][]Column{{c("2"), c("NA"), c("NA")}, {{}, c("NA"), c("NA")}, {c("1"), c("Ann"), c("Oslo")}},
+		},
+	}, {
+		Name:  "Disjoint",
+		T:     full,
+		Other: NewTable([]string{"sku", "price"}, [][]Column{{c("x1"), c("9.99")}}),
+		Want: Table{
+			Headers: []string{"id", "name", "city", "sku", "price"},
+			Rows: [][]Column{
+				{c("1"), c("Ann"), c("Oslo"), c("NA"), c("NA")},
+				{c("NA"), c("NA"), c("NA"), c("x1"), c("9.99")},
+			},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			headers := append([]string(nil), tt.T.Headers...)
+			out, err := tt.T.MergeRelaxed(*tt.Other, "NA")
+			if err != nil {
+				t.Fatalf("MergeRelaxed() error: %v", err)
+			}
+			if !reflect.DeepEqual(out, tt.Want) {
+				t.Errorf("MergeRelaxed() =\n%v\nwant\n%v", out, tt.Want)
+			}
+			if !reflect.DeepEqual(tt.T.Headers, headers) {
+				t.Errorf("MergeRelaxed() changed the headers of its receiver to %q", tt.T.Headers)
+			}
+		})
+	}
+
+	dup := NewTable([]string{"id", "id"}, nil)
+	if _, err := full.MergeRelaxed(*dup, ""); !errors.Is(err, ErrDuplicateColumn) {
+		t.Errorf("MergeRelaxed() with repeated column error = %v, want %v", err, ErrDuplicateColumn)
+	}
+}
+
+func TestTableDropDuplicates(t *testing.T) {
+	tbl := NewTable([]string{"id", "name", "city"}, [][]Column{
+		{c("1"), c("Ann"), c("Oslo")},
+		{c("2"), c("Bob"), c("Rome")},
+		{c("1"), q("Ann"), c("Oslo")},
+		{c("3"), c("Ann"), c("Rome")},
+		{c("4"), c("Bob")},
+		{c("4"), c("Bob"), c("")},
+	})
+	tests := []struct {
+		Name    string
+		KeyCols []string
+		Rows    []int
+		Counts  map[string]int
+	}{{
+		Name:    "AllColumns",
+		KeyCols: nil,
+		Rows:    []int{0, 1, 3, 4},
+		Counts:  map[string]int{"1,Ann,Oslo": 2, "2,Bob,Rome": 1, "3,Ann,Rome": 1, "4,Bob,": 2},
+	}, {
+		Name:    "Partial",
+		KeyCols: []string{"name"},
+		Rows:    []int{0, 1},
+		Counts:  map[string]int{"Ann": 3, "Bob": 3},
+	}, {
+		Name:    "TwoColumns",
+		KeyCols: []string{"name", "city"},
+		Rows:    []int{0, 1, 3, 4},
+		Counts:  map[string]int{"Ann,Oslo": 2, "Bob,Rome": 1, "Ann,Rome": 1, "Bob,": 2},
+	}, {
+		Name:    "KeyOrder",
+		KeyCols: []string{"city", "id"},
+		Rows:    []int{0, 1, 3, 4},
+		Counts:  map[string]int{"Oslo,1": 2, "Rome,2": 1, "Rome,3": 1, ",4": 2},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			out, err := tbl.DropDuplicates(tt.KeyCols)
+			if err != nil {
+				t.Fatalf("DropDuplicates() error: %v", err)
+			}
+			want := Table{Headers: tbl.Headers}
+			for _, r := range tt.Rows {
+				want.Rows = append(want.Rows, tbl.Rows[r])
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf("DropDuplicates() =\n%v\nwant\n%v", out, want)
+			}
+			counts, err := tbl.CountDuplicates(tt.KeyCols)
+			if err != nil {
+				t.Fatalf("CountDuplicates() error: %v", err)
+			}
+			if !reflect.DeepEqual(counts, tt.Counts) {
+				t.Errorf("CountDuplicates() = %v, want %v", counts, tt.Counts)
+			}
+		})
+	}
+
+	distinct := NewTable([]string{"id"}, [][]Column{{c("1")}, {c("2")}, {c("3")}})
+	out, err := distinct.DropDuplicates(nil)
+	if err != nil {
+		t.Fatalf("DropDuplicates() error: %v", err)
+	}
+	if !reflect.DeepEqual(out.Rows, distinct.Rows) {
+		t.Errorf("DropDuplicates() without duplicates = %v, want %v", out.Rows, distinct.Rows)
+	}
+
+	same := NewTable([]string{"a", "b"}, [][]Column{{c("x"), c("y,z")}, {c("x"), q("y,z")}, {c("x"), c("y,z")}})
+	out, err = same.DropDuplicates(nil)
+	if err != nil {
+		t.Fatalf("DropDuplicates() error: %v", err)
+	}
+	if len(out.Rows) != 1 {
+		t.Errorf("DropDuplicates() of identical rows returned %d rows, want 1", len(out.Rows))
+	}
+	counts, _ := same.CountDuplicates(nil)
+	if want := map[string]int{`x,"y,z"`: 3}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("CountDuplicates() = %v, want %v", counts, want)
+	}
+
+	if _, err := tbl.DropDuplicates([]string{"email"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("DropDuplicates() by unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+	if _, err := tbl.CountDuplicates([]string{"email"}); !errors.Is(err, ErrUnknownColumn) {
+		t.Errorf("CountDuplicates() by unknown column error = %v, want %v", err, ErrUnknownColumn)
+	}
+}
+
+func TestTableWriteTo(t *testing.T) {
+	var _ io.WriterTo = (*Table)(nil)
+
+	b := &bytes.Buffer{}
+	n, err := newTestTable().WriteTo(b)
+	if err != nil {
+		t.Fatalf("WriteTo() error: %v", err)
+	}
+	const want = "name,lang\n\"Rob Pike\",go\nKen Thompson,\"C\"\nDennis Ritchie\n"
+	if b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("WriteTo() = %d, want %d", n, len(want))
+	}
+
+	if _, err := newTestTable().WriteTo(errorWriter{}); err == nil {
+		t.Error("WriteTo() with failing writer: expected error")
+	}
+}
+
+func TestTableReadFrom(t *testing.T) {
+	var _ io.ReaderFrom = (*Table)(nil)
+
+	const input = "name,lang\n\"Rob Pike\",go\nKen Thompson,\"C\"\nDennis Ritchie\n"
+	var tbl Table
+	n, err := tbl.ReadFrom(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("ReadFrom() error: %v", err)
+	}
+	if n != int64(len(input)) {
+		t.Errorf("ReadFrom() = %d, want %d", n, len(input))
+	}
+	if want := newTestTable(); !reflect.DeepEqual(&tbl, want) {
+		t.Errorf("ReadFrom() table = %+v, want %+v", tbl, want)
+	}
+
+	// The table can be hashed while it is read.
+	h := sha256.New()
+	if _, err := tbl.ReadFrom(io.TeeReader(strings.NewReader(input), h)); err != nil {
+		t.Fatalf("ReadFrom() error: %v", err)
+	}
+	if sum := sha256.Sum256([]byte(input)); !bytes.Equal(h.Sum(nil), sum[:]) {
+		t.Error("hash of TeeReader differs from hash of input")
+	}
+
+	if _, err := tbl.ReadFrom(strings.NewReader("a,\"b\n")); err == nil {
+		t.Error("ReadFrom() of invalid input: expected error")
+	}
+	if !reflect.DeepEqual(&tbl, newTestTable()) {
+		t.Errorf("ReadFrom() error changed table to %+v", tbl)
+	}
+
+	readErr := errors.New("read error")
+	if _, err := tbl.ReadFrom(io.MultiReader(strings.NewReader("a,b\n"), &failingReader{readErr})); err != readErr {
+		t.Errorf("ReadFrom() error = %v, want %v", err, readErr)
+	}
+}
+
+type failingReader struct{ err error }
+
+func (r *failingReader) Read([]byte) (int, error) { return 0, r.err }
+
+func TestTableEmpty(t *testing.T) {
+	b := &bytes.Buffer{}
+	n, err := (&Table{}).WriteTo(b)
+	if n != 0 || err != nil || b.Len() != 0 {
+		t.Errorf("WriteTo() of empty table = %d, %v and wrote %q", n, err, b.String())
+	}
+
+	tbl := newTestTable()
+	n, err = tbl.ReadFrom(b)
+	if n != 0 || err != nil {
+		t.Errorf("ReadFrom() of empty input = %d, %v", n, err)
+	}
+	if !reflect.DeepEqual(tbl, &Table{}) {
+		t.Errorf("ReadFrom() of empty input gave %+v", tbl)
+	}
+
+	// A table with headers but no rows.
+	tbl = NewTable([]string{"a", "b"}, nil)
+	if _, err := tbl.WriteTo(b); err != nil {
+		t.Fatalf("WriteTo() error: %v", err)
+	}
+	var got Table
+	if _, err := got.ReadFrom(b); err != nil {
+		t.Fatalf("ReadFrom() error: %v", err)
+	}
+	if !reflect.DeepEqual(&got, tbl) {
+		t.Errorf("round trip gave %+v, want %+v", got, tbl)
+	}
+}
+
+func TestTableHeadTail(t *testing.T) {
+	rows := make([][]Column, 5)
+	for i := range rows {
+		rows[i] = []Column{c(strconv.Itoa(i))}
+	}
+	tbl := NewTable([]string{"n"}, rows)
+	values := func(t Table) string {
+		var s []string
+		for _, row := range t.Rows {
+			s = append(s, row[0].Value)
+		}
+		return strings.Join(s, ",")
+	}
+	tests := []struct {
+		Name string
+		Got  Table
+		Want string
+	}{
+		{"Head2", tbl.Head(2), "0,1"},
+		{"Head0", tbl.Head(0), ""},
+		{"HeadAll", tbl.Head(10), "0,1,2,3,4"},
+		{"HeadNegative", tbl.Head(-1), ""},
+		{"Tail2", tbl.Tail(2), "3,4"},
+		{"Tail0", tbl.Tail(0), ""},
+		{"TailAll", tbl.Tail(10), "0,1,2,3,4"},
+		{"Slice", tbl.Slice(1, 4), "1,2,3"},
+		{"SliceEmpty", tbl.Slice(2, 2), ""},
+		{"SliceReversed", tbl.Slice(3, 1), ""},
+		{"SliceNegative", tbl.Slice(-1, 2), ""},
+		{"SlicePastEnd", tbl.Slice(3, 6), ""},
+	}
+	for _, tt := range tests {
+		if got := values(tt.Got); got != tt.Want {
+			t.Errorf("%s rows = %q, want %q", tt.Name, got, tt.Want)
+		}
+		if !reflect.DeepEqual(tt.Got.Headers, tbl.Headers) {
+			t.Errorf("%s headers = %q, want %q", tt.Name, tt.Got.Headers, tbl.Headers)
+		}
+	}
+
+	head := tbl.Head(4)
+	chained := head.Tail(2)
+	if got := values(chained); got != "2,3" {
+		t.Errorf("Head(4).Tail(2) rows = %q, want %q", got, "2,3")
+	}
+	chained.Rows[0][0].Value = "x"
+	if tbl.Rows[2][0].Value != "x" {
+		t.Errorf("Head and Tail copied the fields of the table")
+	}
+	head.Rows = append(head.Rows, []Column{c("new")})
+	if tbl.Rows[4][0].Value != "4" {
+		t.Errorf("appending to Head() overwrote a row of the table")
+	}
+}
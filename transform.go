@@ -0,0 +1,48 @@
+package csv
+
+// BatchTransform replaces each record with the result of calling fn on it
+// and returns the transformed records. Records for which fn returns nil are
+// removed. The transformation is done in place: the result shares the
+// backing array of records, whose contents are overwritten.
+func BatchTransform(records [][]Column, fn func([]Column) []Column) [][]Column {
+	out := records[:0]
+	for _, record := range records {
+		if record = fn(record); record != nil {
+			out = append(out, record)
+		}
+	}
+	// Clear the tail so removed records can be garbage collected.
+	for i := len(out); i < len(records); i++ {
+		records[i] = nil
+	}
+	return out
+}
+
+// columnTransformer is a transform added by Reader.AddTransformer.
+type columnTransformer struct {
+	index int // column index, or -1 for all columns
+	fn    func(Column) Column
+}
+
+// AddTransformer registers fn to be applied to the column at columnIndex
+// of every record read by r, before Read returns it. If columnIndex is -1,
+// fn is applied to every column. Records with no column at columnIndex are
+// left alone. Transforms are applied in the order they were added, after
+// Unescape and SanitizeFields and before the record is checked against
+// a schema. With ReuseRecord set, they modify the reused slice in place.
+func (r *Reader) AddTransformer(columnIndex int, fn func(Column) Column) {
+	r.transformers = append(r.transformers, columnTransformer{columnIndex, fn})
+}
+
+// transform applies the transforms of r to record in place.
+func (r *Reader) transform(record []Column) {
+	for _, t := range r.transformers {
+		if t.index < 0 {
+			for i := range record {
+				record[i] = t.fn(record[i])
+			}
+		} else if t.index < len(record) {
+			record[t.index] = t.fn(record[t.index])
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package csv
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBatchTransform(t *testing.T) {
+	records := [][]Column{
+		{c("1"), c("rob")},
+		{c("2"), c("ken")},
+		{c("3"), c("gri")},
+		{c("4"), c("r")},
+	}
+	out := BatchTransform(records, func(record []Column) []Column {
+		if record[0].Value == "2" || record[0].Value == "4" {
+			return nil
+		}
+		record[1].Value = strings.ToUpper(record[1].Value)
+		return record
+	})
+	want := [][]Column{
+		{c("1"), c("ROB")},
+		{c("3"), c("GRI")},
+	}
+	if !reflect.DeepEqual(out, want) {
+		t.Errorf("BatchTransform() = %v, want %v", out, want)
+	}
+	if &out[0] != &records[0] {
+		t.Error("BatchTransform() did not reuse the backing array")
+	}
+	if records[2] != nil || records[3] != nil {
+		t.Error("BatchTransform() did not clear removed records")
+	}
+
+	out = BatchTransform(out, func([]Column) []Column { return nil })
+	if len(out) != 0 {
+		t.Errorf("BatchTransform() removing all = %v, want empty", out)
+	}
+	if out := BatchTransform(nil, func(r []Column) []Column { return r }); len(out) != 0 {
+		t.Errorf("BatchTransform(nil) = %v, want empty", out)
+	}
+}
+
+func TestAddTransformer(t *testing.T) {
+	r := NewReader(strings.NewReader(" a ,b\n\"c\",\" d \"\ne\n"))
+	r.FieldsPerRecord = -1
+	r.ReuseRecord = true
+	r.AddTransformer(1, func(col Column) Column {
+		col.Value = strings.TrimSpace(col.Value)
+		return col
+	})
+	r.AddTransformer(1, func(col Column) Column {
+		col.Value = "<" + col.Value + ">"
+		return col
+	})
+	r.AddTransformer(-1, func(col Column) Column {
+		col.Value = strings.ToUpper(col.Value)
+		return col
+	})
+	want := [][]Column{
+		{c(" A "), c("<B>")},
+		{q("C"), q("<D>")},
+		{c("E")},
+	}
+	for i, w := range want {
+		got, err := r.Read()
+		if err != nil {
+			t.Fatalf("Read() error: %v", err)
+		}
+		if !reflect.DeepEqual(got, w) {
+			t.Errorf("record %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestAddTransformerPanic(t *testing.T) {
+	r := NewReader(strings.NewReader("a,b\n"))
+	r.IgnorePanic = true
+	r.AddTransformer(0, func(Column) Column { panic("boom") })
+	if _, err := r.Read(); !errors.Is(err, ErrHookPanic) {
+		t.Errorf("Read() error = %v, want %v", err, ErrHookPanic)
+	}
+}
@@ -0,0 +1,94 @@
+package csv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NewUTF16Reader returns a Reader for input that starts with a UTF-16
+// byte order mark, such as CSV files saved by Excel as "Unicode Text"
+// or "CSV UTF-16". The byte order is taken from the mark, and the input
+// is decoded to UTF-8 before it is parsed; byte offsets reported by the
+// Reader refer to the decoded text. Unpaired surrogates are read as the
+// Unicode replacement character.
+//
+// If r does not start with a UTF-16 byte order mark, it is read as UTF-8
+// with StripBOM set. An error is only returned if reading the start of r
+// fails.
+func NewUTF16Reader(r io.Reader) (*Reader, error) {
+	br := bufio.NewReader(r)
+	bom, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	var order binary.ByteOrder
+	switch string(bom) {
+	case "\xff\xfe":
+		order = binary.LittleEndian
+	case "\xfe\xff":
+		order = binary.BigEndian
+	default:
+		cr := NewReader(br)
+		cr.StripBOM = true
+		return cr, nil
+	}
+	br.Discard(len(bom))
+	return NewReader(&utf16Reader{r: br, order: order}), nil
+}
+
+// utf16Reader decodes UTF-16 from r into UTF-8.
+type utf16Reader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	buf   []byte // Decoded bytes
+	out   []byte // Part of buf not yet returned by Read
+	err   error
+}
+
+func (u *utf16Reader) Read(p []byte) (int, error) {
+	for len(u.out) == 0 {
+		if u.err != nil {
+			return 0, u.err
+		}
+		u.fill()
+	}
+	n := copy(p, u.out)
+	u.out = u.out[n:]
+	return n, nil
+}
+
+// fill decodes the next code units into u.out.
+func (u *utf16Reader) fill() {
+	const units = 2048 // Code units decoded at once
+	var enc [utf8.UTFMax]byte
+	u.buf = u.buf[:0]
+	for i := 0; i < units; i++ {
+		b, err := u.r.Peek(2)
+		if len(b) < 2 {
+			if len(b) == 1 && err == io.EOF {
+				// Odd final byte.
+				u.r.Discard(1)
+				u.buf = append(u.buf, string(utf8.RuneError)...)
+			}
+			u.err = err
+			break
+		}
+		rn := rune(u.order.Uint16(b))
+		u.r.Discard(2)
+		if utf16.IsSurrogate(rn) {
+			var low rune = utf8.RuneError
+			if b, _ := u.r.Peek(2); len(b) == 2 {
+				low = rune(u.order.Uint16(b))
+			}
+			if rn = utf16.DecodeRune(rn, low); rn != utf8.RuneError {
+				u.r.Discard(2)
+			}
+		}
+		n := utf8.EncodeRune(enc[:], rn)
+		u.buf = append(u.buf, enc[:n]...)
+	}
+	u.out = u.buf
+}
@@ -0,0 +1,99 @@
+package csv
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewUTF16Reader(t *testing.T) {
+	want := [][]Column{
+		{c("Name"), c("City"), c("Note")},
+		{c("José"), c("Zürich"), q("a, b")},
+		{c("😀"), c("東京"), q("line 1\nline 2")},
+	}
+	for _, file := range []string{"excel-le.csv", "excel-be.csv", "utf8-bom.csv"} {
+		t.Run(file, func(t *testing.T) {
+			data, err := os.ReadFile("testdata/utf16/" + file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := NewUTF16Reader(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("NewUTF16Reader() error: %v", err)
+			}
+			out, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if !reflect.DeepEqual(out, want) {
+				t.Errorf("ReadAll() =\n%v\nwant\n%v", out, want)
+			}
+		})
+	}
+}
+
+func TestUTF16ReaderInvalid(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Input  string
+		Output string
+	}{
+		{"Empty", "", ""},
+		{"OneByte", "a", "a"},
+		{"OddByte", "\xff\xfea\x00b", "a�"},
+		{"UnpairedHigh", "\xff\xfe\x3d\xd8a\x00", "�a"},
+		{"UnpairedLow", "\xfe\xff\xde\x00\x00a", "�a"},
+		{"HighAtEnd", "\xfe\xff\x00a\xd8\x3d", "a�"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r, err := NewUTF16Reader(strings.NewReader(tt.Input))
+			if err != nil {
+				t.Fatalf("NewUTF16Reader() error: %v", err)
+			}
+			r.FieldsPerRecord = -1
+			out, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			var got string
+			if len(out) > 0 {
+				got = out[0][0].Value
+			}
+			if got != tt.Output {
+				t.Errorf("ReadAll() = %q, want %q", got, tt.Output)
+			}
+		})
+	}
+}
+
+func TestUTF16ReaderLong(t *testing.T) {
+	// Spans several decoded chunks, with surrogate pairs at their edges.
+	row := strings.Repeat("😀", 1500) + ",x\n"
+	var b bytes.Buffer
+	b.WriteString("\xff\xfe")
+	for _, u := range []rune(strings.Repeat(row, 3)) {
+		if u > 0xffff {
+			u -= 0x10000
+			hi, lo := 0xd800+(u>>10), 0xdc00+(u&0x3ff)
+			b.Write([]byte{byte(hi), byte(hi >> 8), byte(lo), byte(lo >> 8)})
+		} else {
+			b.Write([]byte{byte(u), byte(u >> 8)})
+		}
+	}
+	r, err := NewUTF16Reader(&b)
+	if err != nil {
+		t.Fatalf("NewUTF16Reader() error: %v", err)
+	}
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	want := []Column{c(strings.Repeat("😀", 1500)), c("x")}
+	if len(out) != 3 || !reflect.DeepEqual(out[2], want) {
+		t.Errorf("ReadAll() read %d records, last %.20v", len(out), out[len(out)-1])
+	}
+}
@@ -6,9 +6,14 @@ package csv
 
 import (
 	"bufio"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"sort"
+	"strconv"
 	"strings"
-	"unicode"
+	"sync"
 	"unicode/utf8"
 )
 
@@ -23,6 +28,8 @@ import (
 // Comma is the field delimiter.
 //
 // If UseCRLF is true, the Writer ends each output line with \r\n instead of \n.
+// Line breaks inside quoted fields are then written as \r\n as well,
+// whether the field holds \n or \r\n; a bare \r is written unchanged.
 //
 // The writes of individual records are buffered.
 // After all data has been written, the client should call the
@@ -32,15 +39,155 @@ import (
 type Writer struct {
 	Comma   rune // Field delimiter (set to ',' by NewWriter)
 	UseCRLF bool // True to use \r\n as the line terminator
-	w       *bufio.Writer
+
+	// Quote is the character enclosing quoted fields. It is set to '"'
+	// by NewWriter. Quote characters inside quoted fields are doubled.
+	// Quote must be a valid rune and must not be \r, \n,
+	// or the Unicode replacement character (0xFFFD).
+	// It must also not be equal to Comma.
+	Quote rune
+
+	// EscapeChar, if not 0, is written before quote characters and before
+	// itself inside quoted fields, instead of doubling quote characters.
+	// It is the counterpart of Reader.EscapeChar, so with EscapeChar set
+	// to '\\', the value a"b is written as "a\"b". EscapeChar must be a
+	// valid rune, must not be \r, \n or the Unicode replacement character
+	// (0xFFFD), and must not be equal to Comma or Quote.
+	EscapeChar rune
+
+	// Comment, if not 0, is the comment character used to mark
+	// lines written by WriteComment and separator lines written by
+	// WriteRecordSeparator.
+	// Comment must be a valid rune and must not be \r, \n,
+	// or the Unicode replacement character (0xFFFD).
+	// It must also not be equal to Comma or Quote.
+	Comment rune
+
+	// RecordSeparator is written by WriteRecordSeparator between groups of
+	// records. It is set to "\n" by NewWriter, giving a blank line.
+	// If UseCRLF is true, each \n or \r\n in RecordSeparator is written
+	// as \r\n.
+	RecordSeparator string
+
+	// NullValue, if non-nil, is written unquoted for every Column with
+	// IsNull set, even if it would otherwise need quotes. It is the
+	// counterpart of Reader.NullValue. If NullValue is nil, NULL columns
+	// are written like other columns.
+	NullValue *string
+
+	// Escape, if non-nil, is called on the value of every field before it
+	// is written. The decision whether to quote the field is made on the
+	// escaped value. It is the counterpart of Reader.Unescape.
+	Escape func(value string) string
+
+	// WriteBOM, if true, makes the Writer start its output with a UTF-8
+	// byte order mark, as expected by some spreadsheet applications.
+	// The mark is written once, when data is first forwarded to the
+	// underlying io.Writer, or by the first flush if there is no data.
+	WriteBOM bool
+
+	// OmitTrailingNewline, if true, leaves out the line terminator at the
+	// end of the output. The terminator of each record is withheld when
+	// the record is flushed, and only forwarded to the underlying
+	// io.Writer once more data follows it.
+	OmitTrailingNewline bool
+
+	// AutoFlush, if true, makes Write flush every record to the underlying
+	// io.Writer as soon as it is written, also when called by WriteAll.
+	// Errors from flushing are returned by Write and reported by Error.
+	AutoFlush bool
+
+	// FlushEvery is the number of records WriteAllFrom writes between
+	// flushes. If FlushEvery is not positive, WriteAllFrom only flushes
+	// before it returns.
+	FlushEvery int
+
+	// QuoteStyle selects which fields are enclosed in quotes.
+	// The default, QuoteMinimal, quotes fields marked Quoted and fields
+	// that cannot be written otherwise.
+	QuoteStyle QuoteStyle
+
+	// StrictQuoting, if true, makes Write return ErrNeedsQuoting instead
+	// of writing a field that needs quotes unquoted under QuoteNever.
+	StrictQuoting bool
+
+	// ForceQuoteValues lists values that are always written quoted, such
+	// as NULL or true for readers that treat them as typed values unless
+	// quoted. A field is quoted if its value, after Escape, equals one of
+	// them, case-sensitively. This overrides QuoteStyle, except that NULL
+	// columns written as NullValue are still not quoted.
+	ForceQuoteValues []string
+
+	// StrictMap, if true, makes WriteMap and WriteMapRecord return
+	// ErrUnknownKey for records with keys missing from the header
+	// instead of ignoring them.
+	StrictMap bool
+
+	// SkipRows lists the 0-based indexes of records that are silently
+	// discarded instead of being written. Indexes count all records
+	// passed to Write, see RecordCount.
+	SkipRows []int
+
+	// EnforceHeaderCount, if true, makes Write return ErrFieldCount for
+	// records whose number of fields differs from that of the header
+	// written by WriteHeader.
+	EnforceHeaderCount bool
+
+	w *bufio.Writer
+
+	// headers is the header row written by WriteHeader.
+	headers []Column
+
+	// out is the underlying io.Writer wrapped by w.
+	out *countingWriter
+
+	// numRecord is the number of records passed to Write.
+	numRecord int
+
+	// bomWritten is set once the byte order mark has been written.
+	bomWritten bool
+
+	// mu guards metrics.
+	mu      sync.Mutex
+	metrics WriteMetrics
 }
 
 // NewWriter returns a new Writer that writes to w.
 func NewWriter(w io.Writer) *Writer {
-	return &Writer{
-		Comma: ',',
-		w:     bufio.NewWriter(w),
+	wr := &Writer{
+		Comma:           ',',
+		Quote:           '"',
+		RecordSeparator: "\n",
 	}
+	wr.out = &countingWriter{w: w, wr: wr}
+	wr.w = bufio.NewWriter(wr.out)
+	return wr
+}
+
+// Clone returns a new Writer writing to out with the same settings as w.
+// The state of w, such as buffered output, the headers given to
+// WriteHeader, the record count and metrics, is not copied.
+func (w *Writer) Clone(out io.Writer) *Writer {
+	c := NewWriter(out)
+	c.Comma = w.Comma
+	c.UseCRLF = w.UseCRLF
+	c.Quote = w.Quote
+	c.EscapeChar = w.EscapeChar
+	c.Comment = w.Comment
+	c.RecordSeparator = w.RecordSeparator
+	c.NullValue = w.NullValue
+	c.Escape = w.Escape
+	c.WriteBOM = w.WriteBOM
+	c.OmitTrailingNewline = w.OmitTrailingNewline
+	c.AutoFlush = w.AutoFlush
+	c.FlushEvery = w.FlushEvery
+	c.QuoteStyle = w.QuoteStyle
+	c.StrictQuoting = w.StrictQuoting
+	c.ForceQuoteValues = append([]string(nil), w.ForceQuoteValues...)
+	c.StrictMap = w.StrictMap
+	c.SkipRows = append([]int(nil), w.SkipRows...)
+	c.EnforceHeaderCount = w.EnforceHeaderCount
+	return c
 }
 
 // Write writes a single CSV record to w along with any necessary quoting.
@@ -48,9 +195,69 @@ func NewWriter(w io.Writer) *Writer {
 // Writes are buffered, so Flush must eventually be called to ensure
 // that the record is written to the underlying io.Writer.
 func (w *Writer) Write(record []Column) error {
-	if !validDelim(w.Comma) {
+	idx := w.numRecord
+	w.numRecord++
+	for _, skip := range w.SkipRows {
+		if skip == idx {
+			return nil
+		}
+	}
+
+	var m WriteMetrics
+	var err error
+	if w.EnforceHeaderCount && w.headers != nil && len(record) != len(w.headers) {
+		err = fmt.Errorf("csv: record %d has %d fields, header has %d: %w", idx, len(record), len(w.headers), ErrFieldCount)
+	} else {
+		err = w.writeRecord(record, &m)
+	}
+	if err == nil && w.AutoFlush {
+		if err = w.flush(); err != nil {
+			// flush already counted the error.
+			return err
+		}
+	}
+	w.mu.Lock()
+	if err != nil {
+		w.metrics.ErrorCount++
+	} else {
+		w.metrics.RecordsWritten++
+		w.metrics.QuotedFieldCount += m.QuotedFieldCount
+		w.metrics.EmptyFieldCount += m.EmptyFieldCount
+	}
+	w.mu.Unlock()
+	return err
+}
+
+// WriteHeader writes headers as a record like Write and remembers it as
+// the header row, returned by Headers. If EnforceHeaderCount is true,
+// later records must have as many fields as headers.
+func (w *Writer) WriteHeader(headers []Column) error {
+	w.headers = append([]Column{}, headers...)
+	return w.Write(headers)
+}
+
+// Headers returns the header row written by WriteHeader,
+// or nil if WriteHeader has not been called.
+func (w *Writer) Headers() []Column {
+	return w.headers
+}
+
+// checkConfig returns an error if the delimiters of w are invalid.
+func (w *Writer) checkConfig() error {
+	if !validDelim(w.Comma) || !validQuote(w.Quote) || w.Quote == w.Comma {
 		return errInvalidDelim
 	}
+	if w.EscapeChar != 0 && (!validQuote(w.EscapeChar) || w.EscapeChar == w.Comma || w.EscapeChar == w.Quote) {
+		return errInvalidDelim
+	}
+	return nil
+}
+
+// writeRecord implements Write, counting the fields it writes into m.
+func (w *Writer) writeRecord(record []Column, m *WriteMetrics) error {
+	if err := w.checkConfig(); err != nil {
+		return err
+	}
 
 	for n, field := range record {
 		if n > 0 {
@@ -59,58 +266,101 @@ func (w *Writer) Write(record []Column) error {
 			}
 		}
 
-		// If we don't have to have a quoted field then just
-		// write out the field and continue to the next field.
-		if !field.Quoted && !w.fieldNeedsQuotes(field.Value) {
-			if _, err := w.w.WriteString(field.Value); err != nil {
-				return err
-			}
-			continue
+		if err := w.writeField(field, m); err != nil {
+			return err
+		}
+	}
+	return w.writeNewline()
+}
+
+// writeField writes a single field, quoting it if necessary,
+// and counts it into m.
+func (w *Writer) writeField(field Column, m *WriteMetrics) error {
+	if field.IsNull && w.NullValue != nil {
+		_, err := w.w.WriteString(*w.NullValue)
+		return err
+	}
+	if w.Escape != nil {
+		field.Value = w.Escape(field.Value)
+	}
+	if field.Value == "" {
+		m.EmptyFieldCount++
+	}
+
+	// If we don't have to have a quoted field then just
+	// write out the field.
+	quote, err := w.quoteField(field)
+	if err != nil {
+		return err
+	}
+	if !quote {
+		quote = w.forceQuote(field.Value)
+	}
+	if !quote {
+		_, err := w.w.WriteString(field.Value)
+		return err
+	}
+
+	m.QuotedFieldCount++
+	if _, err := w.w.WriteRune(w.Quote); err != nil {
+		return err
+	}
+	specials := string(w.Quote) + "\r\n"
+	if w.EscapeChar != 0 {
+		specials += string(w.EscapeChar)
+	}
+	for len(field.Value) > 0 {
+		// Search for special characters.
+		i := strings.IndexAny(field.Value, specials)
+		if i < 0 {
+			i = len(field.Value)
 		}
 
-		if err := w.w.WriteByte('"'); err != nil {
+		// Copy verbatim everything before the special character.
+		if _, err := w.w.WriteString(field.Value[:i]); err != nil {
 			return err
 		}
-		for len(field.Value) > 0 {
-			// Search for special characters.
-			i := strings.IndexAny(field.Value, "\"\r\n")
-			if i < 0 {
-				i = len(field.Value)
-			}
+		field.Value = field.Value[i:]
 
-			// Copy verbatim everything before the special character.
-			if _, err := w.w.WriteString(field.Value[:i]); err != nil {
-				return err
-			}
-			field.Value = field.Value[i:]
-
-			// Encode the special character.
-			if len(field.Value) > 0 {
-				var err error
-				switch field.Value[0] {
-				case '"':
-					_, err = w.w.WriteString(`""`)
-				case '\r':
-					if !w.UseCRLF {
-						err = w.w.WriteByte('\r')
-					}
-				case '\n':
-					if w.UseCRLF {
-						_, err = w.w.WriteString("\r\n")
-					} else {
-						err = w.w.WriteByte('\n')
-					}
+		// Encode the special character.
+		if len(field.Value) > 0 {
+			var err error
+			r, size := utf8.DecodeRuneInString(field.Value)
+			switch r {
+			case w.Quote, w.EscapeChar:
+				// Double the quote, or escape it or the escape character.
+				prefix := w.Quote
+				if w.EscapeChar != 0 {
+					prefix = w.EscapeChar
+				}
+				if _, err = w.w.WriteRune(prefix); err == nil {
+					_, err = w.w.WriteRune(r)
 				}
-				field.Value = field.Value[1:]
-				if err != nil {
-					return err
+			case '\r':
+				// With UseCRLF, \r\n is written as a whole when the \n is
+				// reached. A bare \r is kept.
+				if !w.UseCRLF || !strings.HasPrefix(field.Value, "\r\n") {
+					err = w.w.WriteByte('\r')
+				}
+			case '\n':
+				if w.UseCRLF {
+					_, err = w.w.WriteString("\r\n")
+				} else {
+					err = w.w.WriteByte('\n')
 				}
 			}
-		}
-		if err := w.w.WriteByte('"'); err != nil {
-			return err
+			field.Value = field.Value[size:]
+			if err != nil {
+				return err
+			}
 		}
 	}
+	_, err = w.w.WriteRune(w.Quote)
+	return err
+}
+
+// writeNewline terminates the current line.
+func (w *Writer) writeNewline() error {
 	var err error
 	if w.UseCRLF {
 		_, err = w.w.WriteString("\r\n")
@@ -120,10 +370,174 @@ func (w *Writer) Write(record []Column) error {
 	return err
 }
 
+// WriteEmptyRecord writes a blank line, for formats that use blank lines
+// to separate sections. Readers skip blank lines, so the line does not
+// appear as a record when read back. Unlike records passed to Write,
+// blank lines are not counted by RecordCount.
+func (w *Writer) WriteEmptyRecord() error {
+	err := w.writeNewline()
+	if err != nil {
+		w.mu.Lock()
+		w.metrics.ErrorCount++
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// RecordCount returns the number of records passed to Write so far,
+// including those discarded because of SkipRows.
+func (w *Writer) RecordCount() int {
+	return w.numRecord
+}
+
+// A QuoteStyle determines which fields a Writer encloses in quotes.
+type QuoteStyle int
+
+const (
+	// QuoteMinimal quotes fields marked Quoted and fields containing
+	// Comma, Quote, \r or \n.
+	QuoteMinimal QuoteStyle = iota
+
+	// QuoteAll quotes every field, ignoring Column.Quoted.
+	QuoteAll
+
+	// QuoteNonNumeric quotes every field whose value does not parse as a
+	// float, as well as numeric fields that contain Comma.
+	// Column.Quoted is ignored.
+	QuoteNonNumeric
+
+	// QuoteNever never quotes fields, ignoring Column.Quoted. Fields
+	// containing Comma, a quote or a line break are written as they are,
+	// so the output may not be read back correctly unless StrictQuoting
+	// is set.
+	QuoteNever
+)
+
+// ErrNeedsQuoting is returned by Write if StrictQuoting is set and a field
+// that must be quoted is written with QuoteNever.
+var ErrNeedsQuoting = errors.New("csv: field needs quoting")
+
+// ErrNoSeparator is returned by WriteRecordSeparator if neither
+// RecordSeparator nor Comment is set.
+var ErrNoSeparator = errors.New("csv: no record separator or comment character")
+
+// WriteRecordSeparator writes RecordSeparator to separate logical groups
+// of records. If RecordSeparator is empty, it writes the comment line
+// "# ---" instead, with # replaced by Comment. If Comment is 0 as well,
+// it returns ErrNoSeparator.
+func (w *Writer) WriteRecordSeparator() error {
+	if w.RecordSeparator == "" {
+		if w.Comment == 0 {
+			return ErrNoSeparator
+		}
+		if w.Comment == w.Comma || w.Comment == w.Quote || !validDelim(w.Comment) {
+			return errInvalidDelim
+		}
+		if _, err := w.w.WriteRune(w.Comment); err != nil {
+			return err
+		}
+		if _, err := w.w.WriteString(" ---"); err != nil {
+			return err
+		}
+		return w.writeNewline()
+	}
+
+	sep := w.RecordSeparator
+	for len(sep) > 0 {
+		i := strings.IndexByte(sep, '\n')
+		if i < 0 {
+			i = len(sep)
+		}
+		line := sep[:i]
+		if w.UseCRLF && i < len(sep) {
+			// writeNewline adds the \r of a \r\n already.
+			line = strings.TrimSuffix(line, "\r")
+		}
+		if _, err := w.w.WriteString(line); err != nil {
+			return err
+		}
+		sep = sep[i:]
+		if len(sep) > 0 {
+			if err := w.writeNewline(); err != nil {
+				return err
+			}
+			sep = sep[1:]
+		}
+	}
+	return nil
+}
+
+// ErrNoCommentChar is returned by WriteComment if Comment is not set.
+var ErrNoCommentChar = errors.New("csv: no comment character")
+
+// WriteComment writes text as a comment line starting with Comment, which
+// a Reader with the same Comment skips. Each line of a multi-line text is
+// written as a separate comment line. Buffered records are flushed before
+// the comment is written.
+// If Comment is 0, WriteComment returns ErrNoCommentChar.
+func (w *Writer) WriteComment(text string) error {
+	if w.Comment == 0 {
+		return ErrNoCommentChar
+	}
+	if w.Comment == w.Comma || w.Comment == w.Quote || !validDelim(w.Comment) {
+		return errInvalidDelim
+	}
+	if err := w.flush(); err != nil {
+		return err
+	}
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 {
+			i = len(text)
+		}
+		line := strings.TrimSuffix(text[:i], "\r")
+		if _, err := w.w.WriteRune(w.Comment); err != nil {
+			return err
+		}
+		if _, err := w.w.WriteString(line); err != nil {
+			return err
+		}
+		if err := w.writeNewline(); err != nil {
+			return err
+		}
+		if i == len(text) {
+			return nil
+		}
+		text = text[i+1:]
+	}
+}
+
 // Flush writes any buffered data to the underlying io.Writer.
 // To check if an error occurred during the Flush, call Error.
 func (w *Writer) Flush() {
-	w.w.Flush()
+	w.flush()
+}
+
+// flush flushes the buffer and records the flush in the metrics.
+func (w *Writer) flush() error {
+	var err error
+	if w.w.Buffered() == 0 {
+		// Flushing an empty buffer does not reach countingWriter.
+		err = w.out.writeBOM()
+	}
+	if err == nil {
+		err = w.w.Flush()
+	}
+	w.mu.Lock()
+	w.metrics.FlushCount++
+	if err != nil {
+		w.metrics.ErrorCount++
+	}
+	w.mu.Unlock()
+	return err
+}
+
+// Close flushes any buffered data and returns the first error that
+// occurred while writing, like Flush followed by Error. It does not close
+// the underlying io.Writer. The Writer must not be used after Close.
+func (w *Writer) Close() error {
+	w.flush()
+	return w.Error()
 }
 
 // Error reports any error that has occurred during a previous Write or Flush.
@@ -141,12 +555,227 @@ func (w *Writer) WriteAll(records [][]Column) error {
 			return err
 		}
 	}
-	return w.w.Flush()
+	return w.flush()
+}
+
+// WriteIf writes record using Write if ok(record) returns true.
+// Records rejected by ok do not count towards RecordCount and SkipRows.
+// If ok panics, the panic is recovered and returned as an error wrapping
+// ErrHookPanic, and record is not written.
+func (w *Writer) WriteIf(record []Column, ok func([]Column) bool) error {
+	keep, err := callPredicate(ok, record)
+	if err != nil || !keep {
+		return err
+	}
+	return w.Write(record)
+}
+
+// WriteAllFiltered writes the records for which ok returns true using
+// WriteIf and then calls Flush, returning any error from the Flush.
+// If writing a record fails or ok panics, the records written before
+// are not flushed.
+func (w *Writer) WriteAllFiltered(records [][]Column, ok func([]Column) bool) error {
+	for _, record := range records {
+		if err := w.WriteIf(record, ok); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// callPredicate returns ok(record), recovering a panic inside ok as an
+// error wrapping ErrHookPanic.
+func callPredicate(ok func([]Column) bool, record []Column) (keep bool, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("csv: %w: %v", ErrHookPanic, p)
+		}
+	}()
+	return ok(record), nil
+}
+
+// WriteSingleColumn writes each of values as a record with a single field,
+// quoted as Write would quote it, and then calls Flush, returning any error
+// from the Flush. An empty value is written quoted as "", since a Reader
+// would skip an empty line, so that the values read back by
+// Reader.ReadSingleColumn are the same. Under QuoteNever, it is still
+// written as an empty line.
+func (w *Writer) WriteSingleColumn(values []string) error {
+	record := make([]Column, 1)
+	for _, v := range values {
+		record[0] = Column{Value: v, Quoted: v == ""}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// stackRecordLen is the number of fields up to which WriteStrings and
+// WriteAllStrings convert records without allocating.
+const stackRecordLen = 32
+
+// WriteStrings writes record like Write, taking each string as the Value
+// of an unquoted Column. Records of up to 32 fields are converted without
+// allocating.
+func (w *Writer) WriteStrings(record []string) error {
+	var buf [stackRecordLen]Column
+	return w.writeStrings(record, buf[:])
+}
+
+// WriteAllStrings writes records using WriteStrings and then calls Flush,
+// returning any error from the Flush.
+func (w *Writer) WriteAllStrings(records [][]string) error {
+	var buf [stackRecordLen]Column
+	for _, record := range records {
+		if err := w.writeStrings(record, buf[:]); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// writeStrings writes record, converted into buf if it is large enough.
+func (w *Writer) writeStrings(record []string, buf []Column) error {
+	if len(record) > len(buf) {
+		buf = make([]Column, len(record))
+	}
+	cols := buf[:len(record)]
+	for i, s := range record {
+		cols[i] = Column{Value: s}
+	}
+	return w.Write(cols)
+}
+
+// ErrUnknownKey is returned by WriteMapRecord if StrictMap is set and a
+// record has a key that is not in the header.
+var ErrUnknownKey = errors.New("key not in header")
+
+// WriteMap writes headers as a header row followed by records, using
+// WriteMapRecord, and then calls Flush, returning any error from the Flush.
+func (w *Writer) WriteMap(headers []string, records []map[string]string) error {
+	header := make([]Column, len(headers))
+	for i, h := range headers {
+		header[i] = Column{Value: h}
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := w.WriteMapRecord(headers, record); err != nil {
+			return err
+		}
+	}
+	return w.flush()
+}
+
+// WriteMapRecord writes record as a single CSV record with one field per
+// element of headers, taking the value of the i'th field from
+// record[headers[i]]. Keys missing from record give empty fields. Keys of
+// record that are not in headers are ignored, unless StrictMap is set.
+func (w *Writer) WriteMapRecord(headers []string, record map[string]string) error {
+	if w.StrictMap {
+		var unknown []string
+	keys:
+		for k := range record {
+			for _, h := range headers {
+				if k == h {
+					continue keys
+				}
+			}
+			unknown = append(unknown, k)
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return fmt.Errorf("csv: %w: %q", ErrUnknownKey, unknown)
+		}
+	}
+	row := make([]Column, len(headers))
+	for i, h := range headers {
+		row[i] = Column{Value: record[h]}
+	}
+	return w.Write(row)
+}
+
+// WriteAllFrom writes the records received from rows to w until rows is
+// closed or ctx is done, flushing every w.FlushEvery records and before
+// returning. It returns the number of records written, which excludes
+// those discarded because of SkipRows. If ctx is done
+// first, the records written so far are flushed and ctx.Err() is returned.
+func WriteAllFrom(w *Writer, rows <-chan []Column, ctx context.Context) (int64, error) {
+	var n int64
+	for {
+		if ctx.Err() != nil {
+			if err := w.flush(); err != nil {
+				return n, err
+			}
+			return n, ctx.Err()
+		}
+		select {
+		case <-ctx.Done():
+			continue
+		case record, ok := <-rows:
+			if !ok {
+				return n, w.flush()
+			}
+			before := w.Metrics().RecordsWritten
+			if err := w.Write(record); err != nil {
+				return n, err
+			}
+			if w.Metrics().RecordsWritten == before {
+				// Discarded because of SkipRows.
+				continue
+			}
+			n++
+			if w.FlushEvery > 0 && n%int64(w.FlushEvery) == 0 {
+				if err := w.flush(); err != nil {
+					return n, err
+				}
+			}
+		}
+	}
+}
+
+// quoteField reports whether field is to be enclosed in quotes
+// under w.QuoteStyle.
+func (w *Writer) quoteField(field Column) (bool, error) {
+	switch w.QuoteStyle {
+	case QuoteAll:
+		return true, nil
+	case QuoteNonNumeric:
+		if _, err := strconv.ParseFloat(field.Value, 64); err != nil {
+			return true, nil
+		}
+	case QuoteNever:
+		if w.StrictQuoting && w.fieldNeedsQuotes(field.Value) {
+			return false, ErrNeedsQuoting
+		}
+		return false, nil
+	default:
+		if field.Quoted {
+			return true, nil
+		}
+	}
+	return w.fieldNeedsQuotes(field.Value), nil
+}
+
+// forceQuote reports whether value is listed in w.ForceQuoteValues.
+func (w *Writer) forceQuote(value string) bool {
+	for _, v := range w.ForceQuoteValues {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
 
 // fieldNeedsQuotes reports whether our field must be enclosed in quotes.
-// Fields with a Comma, fields with a quote or newline, and
-// fields which start with a space must be enclosed in quotes.
+// Fields with a Comma, and fields with a Quote or newline
+// must be enclosed in quotes.
+// Unlike encoding/csv, fields which start with a space and the Postgres
+// data terminating string `\.` are not quoted: a Reader keeps the Quoted
+// flag of every field, so records read from RFC 4180 input are written
+// back byte for byte. Set Column.Quoted to quote such fields.
 // We used to quote empty strings, but we do not anymore (as of Go 1.4).
 // The two representations should be equivalent, but Postgres distinguishes
 // quoted vs non-quoted empty string during database imports, and it has
@@ -155,15 +784,15 @@ func (w *Writer) WriteAll(records [][]Column) error {
 // CSV with quoted empty strings strictly less useful.
 // Not quoting the empty string also makes this package match the behavior
 // of Microsoft Excel and Google Drive.
-// For Postgres, quote the data terminating string `\.`.
 func (w *Writer) fieldNeedsQuotes(field string) bool {
 	if field == "" {
 		return false
 	}
-	if field == `\.` || strings.ContainsRune(field, w.Comma) || strings.ContainsAny(field, "\"\r\n") {
-		return true
-	}
+	return strings.ContainsRune(field, w.Comma) || strings.ContainsRune(field, w.Quote) ||
+		strings.ContainsAny(field, "\r\n")
+}
 
-	r1, _ := utf8.DecodeRuneInString(field)
-	return unicode.IsSpace(r1)
+// validQuote reports whether r can be used as a Writer's Quote.
+func validQuote(r rune) bool {
+	return r != 0 && r != '\r' && r != '\n' && utf8.ValidRune(r) && r != utf8.RuneError
 }
@@ -6,8 +6,15 @@ package csv
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 var writeTests = []struct {
@@ -19,7 +26,8 @@ var writeTests = []struct {
 	{Input: [][]Column{{{Value: `"abc"`}}}, Output: `"""abc"""` + "\n"},
 	{Input: [][]Column{{{Value: `a"b`}}}, Output: `"a""b"` + "\n"},
 	{Input: [][]Column{{{Value: `"a"b"`}}}, Output: `"""a""b"""` + "\n"},
-	{Input: [][]Column{{{Value: " abc"}}}, Output: `" abc"` + "\n"},
+	{Input: [][]Column{{{Value: " abc"}}}, Output: " abc\n"},
+	{Input: [][]Column{{{Value: " abc", Quoted: true}}}, Output: `" abc"` + "\n"},
 	{Input: [][]Column{{{Value: "abc,def"}}}, Output: `"abc,def"` + "\n"},
 	{Input: [][]Column{{{Value: "abc"}, {Value: "def"}}}, Output: "abc,def\n"},
 	{Input: [][]Column{{{Value: "abc"}}, {{Value: "def"}}}, Output: "abc\ndef\n"},
@@ -35,7 +43,8 @@ var writeTests = []struct {
 	{Input: [][]Column{{{Value: "a"}, {Value: ""}, {Value: "a"}}}, Output: "a,,a\n"},
 	{Input: [][]Column{{{Value: "a"}, {Value: "a"}, {Value: ""}}}, Output: "a,a,\n"},
 	{Input: [][]Column{{{Value: "a"}, {Value: "a"}, {Value: "a"}}}, Output: "a,a,a\n"},
-	{Input: [][]Column{{{Value: `\.`}}}, Output: "\"\\.\"\n"},
+	{Input: [][]Column{{{Value: `\.`}}}, Output: "\\.\n"},
+	{Input: [][]Column{{{Value: `\.`, Quoted: true}}}, Output: "\"\\.\"\n"},
 	{Input: [][]Column{{{Value: "x09\x41\xb4\x1c"}, {Value: "aktau"}}}, Output: "x09\x41\xb4\x1c,aktau\n"},
 	{Input: [][]Column{{{Value: ",x09\x41\xb4\x1c"}, {Value: "aktau"}}}, Output: "\",x09\x41\xb4\x1c\",aktau\n"},
 	{Input: [][]Column{{{Value: "abc", Quoted: true}}}, Output: `"abc"` + "\n"},
@@ -57,6 +66,477 @@ func TestWrite(t *testing.T) {
 	}
 }
 
+func TestRoundTrip(t *testing.T) {
+	inputs := []string{
+		"a,b,c\n",
+		`"a","b","c"` + "\n",
+		`a,"b",c` + "\n" + `"d",e,"f"` + "\n",
+		`"",,""` + "\n",
+		` leading,"  quoted leading",trailing ` + "\n",
+		`\.,"\."` + "\n",
+		`"a""b","""","x""y""z"` + "\n",
+		"\"multi\nline\",\"comma,\",\"cr\rfield\"\n",
+		"ünïcödé,\"日本語\",λ\n",
+		"first,second\nthird,fourth\n",
+	}
+	for _, in := range inputs {
+		records, err := NewReader(strings.NewReader(in)).ReadAll()
+		if err != nil {
+			t.Fatalf("ReadAll(%q) error: %v", in, err)
+		}
+		b := &bytes.Buffer{}
+		if err := NewWriter(b).WriteAll(records); err != nil {
+			t.Fatalf("WriteAll() error: %v", err)
+		}
+		if b.String() != in {
+			t.Errorf("round trip of %q gave %q", in, b.String())
+		}
+	}
+}
+
+func TestWriteCRLF(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Input  [][]Column
+		Output string
+	}{{
+		Name:   "CRLF",
+		Input:  [][]Column{{c("a"), c("b")}, {c("c"), c("d")}},
+		Output: "a,b\r\nc,d\r\n",
+	}, {
+		Name:   "BareCR",
+		Input:  [][]Column{{c("a"), c("b\rc"), c("d")}},
+		Output: "a,\"b\rc\",d\r\n",
+	}, {
+		Name:   "LFInQuotedField",
+		Input:  [][]Column{{c("a"), c("b\nc"), c("d")}},
+		Output: "a,\"b\r\nc\",d\r\n",
+	}, {
+		Name:   "CRLFInQuotedField",
+		Input:  [][]Column{{c("a"), c("b\r\nc"), c("d")}},
+		Output: "a,\"b\r\nc\",d\r\n",
+	}, {
+		Name:   "TrailingCR",
+		Input:  [][]Column{{c("a\r")}},
+		Output: "\"a\r\"\r\n",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.UseCRLF = true
+			if err := w.WriteAll(tt.Input); err != nil {
+				t.Fatalf("WriteAll() error: %v", err)
+			}
+			if b.String() != tt.Output {
+				t.Errorf("out=%q want %q", b.String(), tt.Output)
+			}
+
+			// The Reader turns \r\n into \n, also inside quoted fields.
+			out, err := NewReader(b).ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			want := unboxCols(tt.Input)
+			for _, record := range want {
+				for i := range record {
+					record[i] = strings.ReplaceAll(record[i], "\r\n", "\n")
+				}
+			}
+			if got := unboxCols(out); !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip:\ngot  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestWriteDelimiters(t *testing.T) {
+	record := []Column{c("a"), c("b;c"), c("d\te"), c(`f"g`), c("h'i"), q("j")}
+	tests := []struct {
+		Name   string
+		Comma  rune
+		Quote  rune
+		Output string
+		Error  error
+	}{
+		{Name: "Semicolon", Comma: ';', Quote: '"', Output: "a;\"b;c\";d\te;\"f\"\"g\";h'i;\"j\"\n"},
+		{Name: "Tab", Comma: '\t', Quote: '"', Output: "a\tb;c\t\"d\te\"\t\"f\"\"g\"\th'i\t\"j\"\n"},
+		{Name: "SingleQuote", Comma: ',', Quote: '\'', Output: "a,b;c,d\te,f\"g,'h''i','j'\n"},
+		{Name: "MultiByte", Comma: '|', Quote: '«', Output: "a|b;c|d\te|f\"g|h'i|«j«\n"},
+		{Name: "QuoteIsComma", Comma: ';', Quote: ';', Error: errInvalidDelim},
+		{Name: "NoQuote", Comma: ',', Quote: 0, Error: errInvalidDelim},
+		{Name: "NewlineQuote", Comma: ',', Quote: '\n', Error: errInvalidDelim},
+		{Name: "QuoteComma", Comma: '"', Quote: '\'', Output: "a\"b;c\"d\te\"'f\"g'\"'h''i'\"'j'\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.Comma = tt.Comma
+			w.Quote = tt.Quote
+			if err := w.Write(record); err != tt.Error {
+				t.Fatalf("Write() error = %v, want %v", err, tt.Error)
+			}
+			w.Flush()
+			if b.String() != tt.Output {
+				t.Errorf("out=%q want %q", b.String(), tt.Output)
+			}
+			if tt.Error != nil || tt.Quote != '"' {
+				return
+			}
+
+			r := NewReader(b)
+			r.Comma = tt.Comma
+			out, err := r.Read()
+			if err != nil {
+				t.Fatalf("Read() error: %v", err)
+			}
+			if got, want := unboxCols([][]Column{out}), unboxCols([][]Column{record}); !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip:\ngot  %q\nwant %q", got, want)
+			}
+		})
+	}
+}
+
+func TestNullValue(t *testing.T) {
+	tests := []struct {
+		Name   string
+		Null   string
+		Input  string
+		Output [][]Column
+	}{{
+		Name:   "Empty",
+		Null:   "",
+		Input:  "a,,\"\",b\n",
+		Output: [][]Column{{c("a"), {IsNull: true}, q(""), c("b")}},
+	}, {
+		Name:   "Keyword",
+		Null:   "NULL",
+		Input:  "NULL,\"NULL\",,null\n",
+		Output: [][]Column{{{IsNull: true}, q("NULL"), c(""), c("null")}},
+	}, {
+		Name:   "MySQL",
+		Null:   `\N`,
+		Input:  "1,\\N,\"\\N\"\n\\N,x,y\n",
+		Output: [][]Column{{c("1"), {IsNull: true}, q(`\N`)}, {{IsNull: true}, c("x"), c("y")}},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			r := NewReader(strings.NewReader(tt.Input))
+			r.NullValue = &tt.Null
+			out, err := r.ReadAll()
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if !reflect.DeepEqual(out, tt.Output) {
+				t.Fatalf("ReadAll() output:\ngot  %v\nwant %v", out, tt.Output)
+			}
+
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.NullValue = &tt.Null
+			if err := w.WriteAll(out); err != nil {
+				t.Fatalf("WriteAll() error: %v", err)
+			}
+			if b.String() != tt.Input {
+				t.Errorf("round trip of %q gave %q", tt.Input, b.String())
+			}
+		})
+	}
+
+	// NullValue is written unquoted even if it needs quotes.
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	null := "N,A"
+	w.NullValue = &null
+	w.Write([]Column{{IsNull: true, Value: "ignored"}, c("N,A")})
+	w.Flush()
+	if want := "N,A,\"N,A\"\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	// Without NullValue, NULL columns are written like other columns.
+	b.Reset()
+	w = NewWriter(b)
+	w.Write([]Column{{IsNull: true}, {IsNull: true, Value: "x,y"}})
+	w.Flush()
+	if want := ",\"x,y\"\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+}
+
+func TestWriteMap(t *testing.T) {
+	headers := []string{"name", "lang", "note"}
+	records := []map[string]string{
+		{"name": "Rob Pike", "lang": "go", "note": "a, b"},
+		{"lang": "C", "name": "Ken Thompson"},
+		{"name": "Dennis", "year": "1941"},
+	}
+
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	if err := w.WriteMap(headers, records); err != nil {
+		t.Fatalf("WriteMap() error: %v", err)
+	}
+	const want = "name,lang,note\nRob Pike,go,\"a, b\"\nKen Thompson,C,\nDennis,,\n"
+	if b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	b.Reset()
+	w = NewWriter(b)
+	w.StrictMap = true
+	err := w.WriteMap(headers, records)
+	if !errors.Is(err, ErrUnknownKey) || !strings.Contains(err.Error(), `"year"`) {
+		t.Errorf("WriteMap() error = %v, want %v naming the key", err, ErrUnknownKey)
+	}
+	w.Flush()
+	if want := "name,lang,note\nRob Pike,go,\"a, b\"\nKen Thompson,C,\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	b.Reset()
+	w = NewWriter(b)
+	w.WriteMapRecord([]string{"b", "a"}, map[string]string{"a": "1", "b": "2"})
+	w.Flush()
+	if want := "2,1\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+}
+
+// chunkWriter records the data passed to each call to Write.
+type chunkWriter struct {
+	chunks []string
+	err    error
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	w.chunks = append(w.chunks, string(p))
+	return len(p), nil
+}
+
+func TestWriteAutoFlush(t *testing.T) {
+	cw := &chunkWriter{}
+	w := NewWriter(cw)
+	w.AutoFlush = true
+	if err := w.Write([]Column{c("a"), c("b,c")}); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+	if want := []string{"a,\"b,c\"\n"}; !reflect.DeepEqual(cw.chunks, want) {
+		t.Errorf("chunks after Write() = %q, want %q", cw.chunks, want)
+	}
+	if err := w.WriteAll([][]Column{{c("d")}, {c("e\nf")}}); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	if want := []string{"a,\"b,c\"\n", "d\n", "\"e\nf\"\n"}; !reflect.DeepEqual(cw.chunks, want) {
+		t.Errorf("chunks after WriteAll() = %q, want %q", cw.chunks, want)
+	}
+	if got := w.Metrics().FlushCount; got != 4 {
+		t.Errorf("FlushCount = %d, want 4", got)
+	}
+
+	// Without AutoFlush, records stay buffered.
+	cw = &chunkWriter{}
+	w = NewWriter(cw)
+	w.Write([]Column{c("a")})
+	if len(cw.chunks) != 0 {
+		t.Errorf("chunks = %q, want none before Flush", cw.chunks)
+	}
+
+	errWrite := errors.New("write failed")
+	w = NewWriter(&chunkWriter{err: errWrite})
+	w.AutoFlush = true
+	if err := w.Write([]Column{c("a")}); err != errWrite {
+		t.Errorf("Write() error = %v, want %v", err, errWrite)
+	}
+	if err := w.Error(); err != errWrite {
+		t.Errorf("Error() = %v, want %v", err, errWrite)
+	}
+	if m := w.Metrics(); m.ErrorCount != 1 || m.RecordsWritten != 0 {
+		t.Errorf("ErrorCount = %d, RecordsWritten = %d, want 1, 0", m.ErrorCount, m.RecordsWritten)
+	}
+}
+
+func TestWriteBOM(t *testing.T) {
+	const bom = "\ufeff"
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	w.WriteBOM = true
+	w.Write([]Column{c("a"), c("b")})
+	w.Flush()
+	w.Write([]Column{c("c"), c("d")})
+	w.Flush()
+	w.Flush()
+	if want := bom + "a,b\nc,d\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+	if n := w.Metrics().BytesWritten; n != int64(b.Len()) {
+		t.Errorf("BytesWritten = %d, want %d", n, b.Len())
+	}
+
+	// The BOM is written even if there is nothing else to write.
+	b.Reset()
+	w = NewWriter(b)
+	w.WriteBOM = true
+	w.Flush()
+	w.Flush()
+	if b.String() != bom {
+		t.Errorf("out=%q want %q", b.String(), bom)
+	}
+
+	// Large records are forwarded before the first flush.
+	cw := &chunkWriter{}
+	w = NewWriter(cw)
+	w.WriteBOM = true
+	long := strings.Repeat("x", 10000)
+	w.WriteAll([][]Column{{c(long)}, {c(long)}})
+	if got := strings.Join(cw.chunks, ""); got != bom+long+"\n"+long+"\n" {
+		t.Errorf("output has %d BOMs and length %d", strings.Count(got, bom), len(got))
+	}
+	if cw.chunks[0] != bom {
+		t.Errorf("first chunk is %.10q, want the BOM", cw.chunks[0])
+	}
+
+	b.Reset()
+	w = NewWriter(b)
+	w.Write([]Column{c("a")})
+	w.Flush()
+	if strings.Contains(b.String(), bom) {
+		t.Errorf("out=%q contains BOM without WriteBOM", b.String())
+	}
+
+	// A Reader with StripBOM reads the output back.
+	b.Reset()
+	w = NewWriter(b)
+	w.WriteBOM = true
+	w.Write([]Column{c("a"), q("b")})
+	w.Flush()
+	r := NewReader(b)
+	r.StripBOM = true
+	if out, err := r.ReadAll(); err != nil || !reflect.DeepEqual(out, [][]Column{{c("a"), q("b")}}) {
+		t.Errorf("ReadAll() = %v, %v", out, err)
+	}
+}
+
+func TestWriteEscapeChar(t *testing.T) {
+	records := [][]Column{
+		{c(`a"b`), c(`c\d`), c("plain")},
+		{c("e\nf"), c("g\rh"), c(`\"`)},
+		{q(""), c("i,j"), c(`k\`)},
+	}
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	w.EscapeChar = '\\'
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	const want = `"a\"b",c\d,plain` + "\n" +
+		"\"e\nf\",\"g\rh\"," + `"\\\""` + "\n" +
+		`"","i,j",k\` + "\n"
+	if b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	r := NewReader(b)
+	r.EscapeChar = '\\'
+	r.DisableDoubleQuoteEscape = true
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if got, want := unboxCols(out), unboxCols(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip:\ngot  %q\nwant %q", got, want)
+	}
+
+	for _, esc := range []rune{',', '"', '\n', utf8.RuneError} {
+		w := NewWriter(b)
+		w.EscapeChar = esc
+		if err := w.Write([]Column{c("a")}); err != errInvalidDelim {
+			t.Errorf("Write() with EscapeChar %q: error = %v, want %v", esc, err, errInvalidDelim)
+		}
+	}
+}
+
+func TestWriteQuoteStyle(t *testing.T) {
+	record := []Column{c("abc"), q("1.5"), c(""), c("-2e3"), c("a,b"), q("x")}
+	tests := []struct {
+		Name   string
+		Style  QuoteStyle
+		Strict bool
+		Comma  rune
+		Input  []Column
+		Output string
+		Error  error
+	}{
+		{Name: "Minimal", Style: QuoteMinimal, Input: record, Output: `abc,"1.5",,-2e3,"a,b","x"` + "\n"},
+		{Name: "All", Style: QuoteAll, Input: record, Output: `"abc","1.5","","-2e3","a,b","x"` + "\n"},
+		{Name: "NonNumeric", Style: QuoteNonNumeric, Input: record, Output: `"abc",1.5,"",-2e3,"a,b","x"` + "\n"},
+		{Name: "NonNumericComma", Style: QuoteNonNumeric, Comma: '.', Input: []Column{c("1.5"), c("2")}, Output: `"1.5".2` + "\n"},
+		{Name: "Never", Style: QuoteNever, Input: record, Output: "abc,1.5,,-2e3,a,b,x\n"},
+		{Name: "NeverQuote", Style: QuoteNever, Input: []Column{c(`a"b`)}, Output: `a"b` + "\n"},
+		{Name: "NeverStrict", Style: QuoteNever, Strict: true, Input: []Column{q("abc"), c("x y")}, Output: "abc,x y\n"},
+		{Name: "NeverStrictComma", Style: QuoteNever, Strict: true, Input: []Column{c("a,b")}, Error: ErrNeedsQuoting},
+		{Name: "NeverStrictNewline", Style: QuoteNever, Strict: true, Input: []Column{c("a\nb")}, Error: ErrNeedsQuoting},
+		{Name: "MinimalStrict", Style: QuoteMinimal, Strict: true, Input: []Column{c("a,b")}, Output: `"a,b"` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.QuoteStyle = tt.Style
+			w.StrictQuoting = tt.Strict
+			if tt.Comma != 0 {
+				w.Comma = tt.Comma
+			}
+			err := w.Write(tt.Input)
+			if err != tt.Error {
+				t.Fatalf("Write() error = %v, want %v", err, tt.Error)
+			}
+			if err != nil {
+				return
+			}
+			w.Flush()
+			if b.String() != tt.Output {
+				t.Errorf("out=%q want %q", b.String(), tt.Output)
+			}
+		})
+	}
+}
+
+func TestWriteForceQuoteValues(t *testing.T) {
+	record := []Column{c("NULL"), c("null"), c("true"), q("x"), c("y"), {IsNull: true}}
+	null := "NULL"
+	tests := []struct {
+		Name   string
+		Values []string
+		Style  QuoteStyle
+		Output string
+	}{
+		{Name: "Empty", Output: `NULL,null,true,"x",y,NULL` + "\n"},
+		{Name: "Minimal", Values: []string{"NULL", "true"}, Output: `"NULL",null,"true","x",y,NULL` + "\n"},
+		{Name: "Never", Values: []string{"NULL"}, Style: QuoteNever, Output: `"NULL",null,true,x,y,NULL` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.ForceQuoteValues = tt.Values
+			w.QuoteStyle = tt.Style
+			w.NullValue = &null
+			if err := w.Write(record); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+			w.Flush()
+			if b.String() != tt.Output {
+				t.Errorf("out=%q want %q", b.String(), tt.Output)
+			}
+		})
+	}
+}
+
 type errorWriter struct{}
 
 func (e errorWriter) Write(b []byte) (int, error) {
@@ -83,3 +563,589 @@ func TestError(t *testing.T) {
 		t.Error("Error should not be nil")
 	}
 }
+
+func TestWriteAllFrom(t *testing.T) {
+	rows := make(chan []Column, 1000)
+	var want bytes.Buffer
+	for i := 0; i < 1000; i++ {
+		rows <- []Column{{Value: fmt.Sprint(i)}, {Value: "x,y"}}
+		fmt.Fprintf(&want, "%d,\"x,y\"\n", i)
+	}
+	close(rows)
+
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	w.FlushEvery = 300
+	n, err := WriteAllFrom(w, rows, context.Background())
+	if err != nil {
+		t.Fatalf("WriteAllFrom() error: %v", err)
+	}
+	if n != 1000 {
+		t.Errorf("WriteAllFrom() = %d records, want 1000", n)
+	}
+	if b.String() != want.String() {
+		t.Errorf("WriteAllFrom() output differs from the records sent")
+	}
+	if got := w.Metrics().FlushCount; got != 4 {
+		t.Errorf("FlushCount = %d, want 4", got)
+	}
+
+	// Records discarded because of SkipRows are not counted.
+	rows = make(chan []Column, 10)
+	for i := 0; i < 10; i++ {
+		rows <- []Column{{Value: fmt.Sprint(i)}}
+	}
+	close(rows)
+	b.Reset()
+	w = NewWriter(b)
+	w.SkipRows = []int{0, 1, 2, 5}
+	w.FlushEvery = 3
+	n, err = WriteAllFrom(w, rows, context.Background())
+	if err != nil {
+		t.Fatalf("WriteAllFrom() with SkipRows error: %v", err)
+	}
+	if n != 6 || b.String() != "3\n4\n6\n7\n8\n9\n" {
+		t.Errorf("WriteAllFrom() with SkipRows = %d records, output %q; want 6 records", n, b.String())
+	}
+	if got := w.Metrics().FlushCount; got != 3 {
+		t.Errorf("FlushCount with SkipRows = %d, want 3", got)
+	}
+
+	// Records sent before the cancellation are flushed.
+	rows = make(chan []Column)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		rows <- []Column{{Value: "first"}}
+		cancel()
+	}()
+	b.Reset()
+	w = NewWriter(b)
+	n, err = WriteAllFrom(w, rows, ctx)
+	if err != context.Canceled {
+		t.Errorf("WriteAllFrom() error = %v, want %v", err, context.Canceled)
+	}
+	if n != 1 || b.String() != "first\n" {
+		t.Errorf("WriteAllFrom() = %d records, output %q; want 1 record, %q", n, b.String(), "first\n")
+	}
+}
+
+func TestWriteSkipRows(t *testing.T) {
+	var records [][]Column
+	for i := 0; i < 8; i++ {
+		records = append(records, []Column{{Value: fmt.Sprint(i)}, {Value: "a,b"}})
+	}
+	var filtered [][]Column
+	for i, record := range records {
+		if i != 0 && i != 5 && i != 7 {
+			filtered = append(filtered, record)
+		}
+	}
+
+	got := &bytes.Buffer{}
+	w := NewWriter(got)
+	w.SkipRows = []int{0, 5, 7}
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	want := &bytes.Buffer{}
+	NewWriter(want).WriteAll(filtered)
+	if got.String() != want.String() {
+		t.Errorf("out=%q want %q", got.String(), want.String())
+	}
+	if n := w.RecordCount(); n != len(records) {
+		t.Errorf("RecordCount() = %d, want %d", n, len(records))
+	}
+	if n := w.Metrics().RecordsWritten; n != int64(len(filtered)) {
+		t.Errorf("RecordsWritten = %d, want %d", n, len(filtered))
+	}
+}
+
+func TestWriteEmptyRecord(t *testing.T) {
+	for _, useCRLF := range []bool{false, true} {
+		b := &bytes.Buffer{}
+		w := NewWriter(b)
+		w.UseCRLF = useCRLF
+		w.Write([]Column{{Value: "header"}})
+		w.WriteEmptyRecord()
+		w.Write([]Column{{Value: "a"}})
+		w.Write([]Column{{Value: "b"}})
+		w.WriteEmptyRecord()
+		w.WriteEmptyRecord()
+		w.Write([]Column{{Value: "footer"}})
+		w.Flush()
+		if err := w.Error(); err != nil {
+			t.Fatalf("Error() = %v", err)
+		}
+
+		nl := "\n"
+		if useCRLF {
+			nl = "\r\n"
+		}
+		want := strings.Join([]string{"header", "", "a", "b", "", "", "footer", ""}, nl)
+		if b.String() != want {
+			t.Errorf("UseCRLF=%v: out=%q want %q", useCRLF, b.String(), want)
+		}
+		if n := w.RecordCount(); n != 4 {
+			t.Errorf("RecordCount() = %d, want 4", n)
+		}
+
+		records, err := NewReader(b).ReadAll()
+		if err != nil || len(records) != 4 {
+			t.Errorf("ReadAll() = %v, %v; want 4 records", records, err)
+		}
+	}
+}
+
+func TestWriteRecordSeparator(t *testing.T) {
+	sections := [][][]Column{
+		{{{Value: "title"}}, {{Value: "report"}}},
+		{{{Value: "a"}, {Value: "1"}}, {{Value: "b"}, {Value: "2"}}},
+		{{{Value: "total"}, {Value: "3"}}},
+	}
+	tests := []struct {
+		Name      string
+		UseCRLF   bool
+		Separator string
+		Comment   rune
+		Output    string
+		Error     error
+	}{{
+		Name:      "BlankLine",
+		Separator: "\n",
+		Output:    "title\nreport\n\na,1\nb,2\n\ntotal,3\n",
+	}, {
+		Name:      "BlankLineCRLF",
+		UseCRLF:   true,
+		Separator: "\n",
+		Output:    "title\r\nreport\r\n\r\na,1\r\nb,2\r\n\r\ntotal,3\r\n",
+	}, {
+		Name:      "Custom",
+		Separator: "===\n",
+		Output:    "title\nreport\n===\na,1\nb,2\n===\ntotal,3\n",
+	}, {
+		Name:      "CustomCRLF",
+		UseCRLF:   true,
+		Separator: "===\r\n",
+		Output:    "title\r\nreport\r\n===\r\na,1\r\nb,2\r\n===\r\ntotal,3\r\n",
+	}, {
+		Name:      "CRLFSeparator",
+		UseCRLF:   true,
+		Separator: "\r\n",
+		Output:    "title\r\nreport\r\n\r\na,1\r\nb,2\r\n\r\ntotal,3\r\n",
+	}, {
+		Name:      "CRLFSeparatorLF",
+		Separator: "\r\n",
+		Output:    "title\nreport\n\r\na,1\nb,2\n\r\ntotal,3\n",
+	}, {
+		Name:    "Comment",
+		Comment: '#',
+		Output:  "title\nreport\n# ---\na,1\nb,2\n# ---\ntotal,3\n",
+	}, {
+		Name:  "None",
+		Error: ErrNoSeparator,
+	}, {
+		Name:    "BadComment",
+		Comment: ',',
+		Error:   errInvalidDelim,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			w := NewWriter(b)
+			w.UseCRLF = tt.UseCRLF
+			w.RecordSeparator = tt.Separator
+			w.Comment = tt.Comment
+			for i, section := range sections {
+				if i > 0 {
+					if err := w.WriteRecordSeparator(); err != tt.Error {
+						t.Fatalf("WriteRecordSeparator() error = %v, want %v", err, tt.Error)
+					}
+					if tt.Error != nil {
+						return
+					}
+				}
+				for _, record := range section {
+					w.Write(record)
+				}
+			}
+			w.Flush()
+			if b.String() != tt.Output {
+				t.Errorf("out=%q want %q", b.String(), tt.Output)
+			}
+		})
+	}
+	if w := NewWriter(nil); w.RecordSeparator != "\n" {
+		t.Errorf("NewWriter() RecordSeparator = %q, want %q", w.RecordSeparator, "\n")
+	}
+}
+
+// mysqlEscape applies the backslash escapes of MySQL's OUTFILE format.
+var mysqlEscape = strings.NewReplacer(
+	"\\", `\\`,
+	"\x00", `\0`,
+	"\b", `\b`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\t", `\t`,
+	"\x1a", `\Z`,
+).Replace
+
+func TestWriteComment(t *testing.T) {
+	records := [][]Column{{c("a"), q("b")}, {c("c"), q("d,e")}}
+
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	w.Comment = '#'
+	if err := w.WriteComment("generated by test"); err != nil {
+		t.Fatalf("WriteComment() error: %v", err)
+	}
+	w.Write(records[0])
+	if err := w.WriteComment("two\r\nlines"); err != nil {
+		t.Fatalf("WriteComment() error: %v", err)
+	}
+	w.Write(records[1])
+	w.Flush()
+	const want = "#generated by test\na,\"b\"\n#two\n#lines\nc,\"d,e\"\n"
+	if b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	r := NewReader(b)
+	r.Comment = '#'
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if !reflect.DeepEqual(out, records) {
+		t.Errorf("round trip:\ngot  %v\nwant %v", out, records)
+	}
+	if got := r.Metrics().CommentLinesSkipped; got != 3 {
+		t.Errorf("CommentLinesSkipped = %d, want 3", got)
+	}
+
+	// The comment is written after the buffered record.
+	b.Reset()
+	w = NewWriter(b)
+	w.Comment = ';'
+	w.UseCRLF = true
+	w.Write(records[0])
+	w.WriteComment("")
+	w.Flush()
+	if want := "a,\"b\"\r\n;\r\n"; b.String() != want {
+		t.Errorf("out=%q want %q", b.String(), want)
+	}
+
+	if err := NewWriter(b).WriteComment("x"); err != ErrNoCommentChar {
+		t.Errorf("WriteComment() error = %v, want %v", err, ErrNoCommentChar)
+	}
+	w = NewWriter(b)
+	w.Comment = ','
+	if err := w.WriteComment("x"); err != errInvalidDelim {
+		t.Errorf("WriteComment() error = %v, want %v", err, errInvalidDelim)
+	}
+}
+
+func TestWriteEscape(t *testing.T) {
+	records := [][]Column{
+		{{Value: "line1\nline2"}, {Value: "tab\there"}},
+		{{Value: `back\slash`}, {Value: "cr\rlf\n"}},
+		{{Value: "nul\x00"}, {Value: "comma,\n"}},
+		{{Value: `\n`}, {Value: ""}},
+	}
+
+	b := &bytes.Buffer{}
+	w := NewWriter(b)
+	w.Escape = mysqlEscape
+	if err := w.WriteAll(records); err != nil {
+		t.Fatalf("WriteAll() error: %v", err)
+	}
+	want := `line1\nline2,tab\there` + "\n" +
+		`back\\slash,cr\rlf\n` + "\n" +
+		`nul\0,"comma,\n"` + "\n" +
+		`\\n,` + "\n"
+	if out := b.String(); out != want {
+		t.Errorf("out=%q want %q", out, want)
+	}
+
+	r := NewReader(b)
+	r.Unescape = mysqlUnescape
+	out, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error: %v", err)
+	}
+	if !reflect.DeepEqual(unboxCols(out), unboxCols(records)) {
+		t.Errorf("round trip:\ngot  %q\nwant %q", unboxCols(out), unboxCols(records))
+	}
+}
+
+func TestWriteSingleColumn(t *testing.T) {
+	values := []string{"plain", "a,b", "", `say "hi"`, "two\nlines", " padded "}
+	var b strings.Builder
+	w := NewWriter(&b)
+	if err := w.WriteSingleColumn(values); err != nil {
+		t.Fatalf("WriteSingleColumn() error: %v", err)
+	}
+	want := "plain\n\"a,b\"\n\"\"\n\"say \"\"hi\"\"\"\n\"two\nlines\"\n padded \n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteSingleColumn() output:\ngot  %q\nwant %q", got, want)
+	}
+
+	r := NewReader(strings.NewReader(b.String()))
+	got, err := r.ReadSingleColumn()
+	if err != nil {
+		t.Fatalf("ReadSingleColumn() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("ReadSingleColumn() = %q, want %q", got, values)
+	}
+
+	b.Reset()
+	w = NewWriter(&b)
+	w.Comma = ';'
+	w.WriteSingleColumn([]string{"a;b", "a,b"})
+	if got, want := b.String(), "\"a;b\"\na,b\n"; got != want {
+		t.Errorf("WriteSingleColumn() with Comma ';' = %q, want %q", got, want)
+	}
+}
+
+func TestWriteHeader(t *testing.T) {
+	var b strings.Builder
+	w := NewWriter(&b)
+	w.EnforceHeaderCount = true
+	if err := w.Write([]Column{c("before"), c("header")}); err != nil {
+		t.Fatalf("Write() before WriteHeader error: %v", err)
+	}
+	if w.Headers() != nil {
+		t.Errorf("Headers() before WriteHeader = %v, want nil", w.Headers())
+	}
+	header := []Column{c("id"), q("name"), c("city")}
+	if err := w.WriteHeader(header); err != nil {
+		t.Fatalf("WriteHeader() error: %v", err)
+	}
+	header[0] = c("changed")
+	if got, want := w.Headers(), []Column{c("id"), q("name"), c("city")}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Headers() = %v, want %v", got, want)
+	}
+	if err := w.Write([]Column{c("1"), c("Rob"), c("Sydney")}); err != nil {
+		t.Errorf("Write() of matching record error: %v", err)
+	}
+	if err := w.Write([]Column{c("2"), c("Ken")}); !errors.Is(err, ErrFieldCount) {
+		t.Errorf("Write() of short record error = %v, want %v", err, ErrFieldCount)
+	}
+	w.Flush()
+	if got, want := b.String(), "before,header\nid,\"name\",city\n1,Rob,Sydney\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+	if n := w.Metrics().ErrorCount; n != 1 {
+		t.Errorf("ErrorCount = %d, want 1", n)
+	}
+
+	w.EnforceHeaderCount = false
+	if err := w.Write([]Column{c("3")}); err != nil {
+		t.Errorf("Write() without EnforceHeaderCount error: %v", err)
+	}
+}
+
+func TestWriterClone(t *testing.T) {
+	var b1, b2 bytes.Buffer
+	w := NewWriter(&b1)
+	w.Comma = ';'
+	w.UseCRLF = true
+	w.QuoteStyle = QuoteAll
+	w.SkipRows = []int{1}
+	if err := w.WriteHeader([]Column{c("a"), c("b")}); err != nil {
+		t.Fatalf("WriteHeader() error: %v", err)
+	}
+
+	cl := w.Clone(&b2)
+	cl.Comma = '|'
+	cl.SkipRows[0] = 0
+	if w.Comma != ';' || w.SkipRows[0] != 1 {
+		t.Errorf("original changed to Comma %q, SkipRows %v", w.Comma, w.SkipRows)
+	}
+	if cl.Headers() != nil {
+		t.Errorf("Headers() of clone = %v, want nil", cl.Headers())
+	}
+	cl.Write([]Column{c("x"), c("y")})
+	cl.Write([]Column{c("z"), c("w")})
+	cl.Flush()
+	if want := "\"z\"|\"w\"\r\n"; b2.String() != want {
+		t.Errorf("clone wrote %q, want %q", b2.String(), want)
+	}
+	if b1.Len() != 0 {
+		t.Errorf("clone flushed the original's buffer: %q", b1.String())
+	}
+}
+
+func TestWriteStrings(t *testing.T) {
+	long := make([]string, stackRecordLen+1)
+	for i := range long {
+		long[i] = strconv.Itoa(i)
+	}
+	records := [][]string{{"a", "b,c"}, {}, long, {"\"d\""}}
+
+	var b1, b2 bytes.Buffer
+	w := NewWriter(&b1)
+	if err := w.WriteAllStrings(records); err != nil {
+		t.Fatalf("WriteAllStrings() error: %v", err)
+	}
+	want := NewWriter(&b2)
+	for _, record := range records {
+		cols := make([]Column, len(record))
+		for i, s := range record {
+			cols[i] = Column{Value: s}
+		}
+		want.Write(cols)
+	}
+	want.Flush()
+	if b1.String() != b2.String() {
+		t.Errorf("WriteAllStrings() wrote %q, want %q", b1.String(), b2.String())
+	}
+
+	b1.Reset()
+	w.WriteStrings([]string{"x", "y z"})
+	w.Flush()
+	if got, want := b1.String(), "x,y z\n"; got != want {
+		t.Errorf("WriteStrings() wrote %q, want %q", got, want)
+	}
+
+	w = NewWriter(io.Discard)
+	record := []string{"a", "b", "c", "d"}
+	if n := testing.AllocsPerRun(100, func() { w.WriteStrings(record) }); n != 0 {
+		t.Errorf("WriteStrings() allocs = %v, want 0", n)
+	}
+}
+
+var benchmarkStrings = [][]string{
+	{"x", "y", "z", "w"},
+	{"x", "y", "z", ""},
+	{"x", "y,z", "", ""},
+	{"x\"", "", "", ""},
+}
+
+func BenchmarkWriteAllStrings(b *testing.B) {
+	b.ReportAllocs()
+	w := NewWriter(io.Discard)
+	for i := 0; i < b.N; i++ {
+		w.WriteAllStrings(benchmarkStrings)
+	}
+}
+
+func BenchmarkWriteAllConverted(b *testing.B) {
+	b.ReportAllocs()
+	w := NewWriter(io.Discard)
+	for i := 0; i < b.N; i++ {
+		for _, record := range benchmarkStrings {
+			cols := make([]Column, len(record))
+			for j, s := range record {
+				cols[j] = Column{Value: s}
+			}
+			w.Write(cols)
+		}
+		w.Flush()
+	}
+}
+
+func TestWriteIf(t *testing.T) {
+	records := [][]Column{{c("a"), c("1")}, {c("b"), c("2")}, {c("c"), c("3")}}
+	odd := func(record []Column) bool { return record[1].Value != "2" }
+
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	w.SkipRows = []int{1}
+	if err := w.WriteAllFiltered(records, odd); err != nil {
+		t.Fatalf("WriteAllFiltered() error: %v", err)
+	}
+	// The rejected record does not count, so SkipRows drops the third one.
+	if got, want := b.String(), "a,1\n"; got != want {
+		t.Errorf("WriteAllFiltered() wrote %q, want %q", got, want)
+	}
+	if n := w.RecordCount(); n != 2 {
+		t.Errorf("RecordCount() = %d, want 2", n)
+	}
+
+	b.Reset()
+	w = NewWriter(&b)
+	if err := w.WriteAllFiltered(records, func([]Column) bool { return false }); err != nil {
+		t.Errorf("WriteAllFiltered() rejecting all error: %v", err)
+	}
+	if b.Len() != 0 || w.RecordCount() != 0 {
+		t.Errorf("WriteAllFiltered() rejecting all wrote %q, %d records", b.String(), w.RecordCount())
+	}
+
+	w.WriteIf(records[0], odd)
+	w.WriteIf(records[1], odd)
+	w.Flush()
+	if got, want := b.String(), "a,1\n"; got != want {
+		t.Errorf("WriteIf() wrote %q, want %q", got, want)
+	}
+
+	b.Reset()
+	w = NewWriter(&b)
+	err := w.WriteAllFiltered(records, func(record []Column) bool {
+		if record[0].Value == "b" {
+			panic("boom")
+		}
+		return true
+	})
+	if !errors.Is(err, ErrHookPanic) || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("WriteAllFiltered() with panicking filter error = %v, want %v", err, ErrHookPanic)
+	}
+	w.Flush()
+	if got, want := b.String(), "a,1\n"; got != want {
+		t.Errorf("WriteAllFiltered() with panicking filter wrote %q, want %q", got, want)
+	}
+}
+
+func TestWriteOmitTrailingNewline(t *testing.T) {
+	tests := []struct {
+		Name    string
+		Records [][]Column
+		UseCRLF bool
+		Omit    bool
+		Output  string
+	}{
+		{Name: "Single", Records: [][]Column{{c("a"), c("b")}}, Output: "a,b\n"},
+		{Name: "SingleOmit", Records: [][]Column{{c("a"), c("b")}}, Omit: true, Output: "a,b"},
+		{Name: "Multi", Records: [][]Column{{c("a")}, {c("b")}}, Output: "a\nb\n"},
+		{Name: "MultiOmit", Records: [][]Column{{c("a")}, {c("b")}}, Omit: true, Output: "a\nb"},
+		{Name: "CRLFOmit", Records: [][]Column{{c("a")}, {c("b\r\nc")}}, UseCRLF: true, Omit: true, Output: "a\r\n\"b\r\nc\""},
+		{Name: "QuotedNewlineOmit", Records: [][]Column{{c("a\n")}}, Omit: true, Output: "\"a\n\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			var b bytes.Buffer
+			w := NewWriter(&b)
+			w.UseCRLF = tt.UseCRLF
+			w.OmitTrailingNewline = tt.Omit
+			// Flush after each record, so that terminators are withheld
+			// and forwarded again.
+			for _, record := range tt.Records {
+				w.Write(record)
+				w.Flush()
+			}
+			if err := w.Error(); err != nil {
+				t.Fatalf("Error() = %v", err)
+			}
+			if b.String() != tt.Output {
+				t.Errorf("output = %q, want %q", b.String(), tt.Output)
+			}
+			if n := w.Metrics().BytesWritten; n != int64(len(tt.Output)) {
+				t.Errorf("BytesWritten = %d, want %d", n, len(tt.Output))
+			}
+		})
+	}
+
+	// A terminator split across flushes of the internal buffer is withheld
+	// as a whole.
+	var b bytes.Buffer
+	w := NewWriter(&b)
+	w.UseCRLF = true
+	w.OmitTrailingNewline = true
+	long := strings.Repeat("x", 4095)
+	w.Write([]Column{c(long)})
+	w.Flush()
+	if want := long; b.String() != want {
+		t.Errorf("output ends with %q, want no terminator", b.String()[len(b.String())-2:])
+	}
+}
@@ -0,0 +1,22 @@
+package csv
+
+import "archive/zip"
+
+// NewZipCSVWriter adds a compressed entry called name to zw and returns a
+// Writer writing to it. The entry is complete once the Writer is closed
+// with Close, which does not close zw: further entries may be added, and
+// zw must still be closed to finish the archive.
+func NewZipCSVWriter(zw *zip.Writer, name string) (*Writer, error) {
+	fw, err := zw.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriter(fw), nil
+}
+
+// NewZipCSVReader opens the entry called name in zr and returns a Reader
+// reading from it. The caller should call Close when done to release the
+// entry.
+func NewZipCSVReader(zr *zip.Reader, name string) (*Reader, error) {
+	return NewFSReader(zr, name)
+}
@@ -0,0 +1,67 @@
+package csv
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func TestZipCSV(t *testing.T) {
+	records := [][]Column{
+		{c("name"), c("note")},
+		{q("Rob Pike"), c("go")},
+		{c("Ken Thompson"), q("multi\nline, \"quoted\"")},
+		{c(""), q("")},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range []string{"a.csv", "b.csv"} {
+		w, err := NewZipCSVWriter(zw, name)
+		if err != nil {
+			t.Fatalf("NewZipCSVWriter(%q) error: %v", name, err)
+		}
+		for _, record := range records {
+			if err := w.Write(record); err != nil {
+				t.Fatalf("Write() error: %v", err)
+			}
+		}
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close() error: %v", err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("zip Close() error: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error: %v", err)
+	}
+	if zr.File[0].Method != zip.Deflate {
+		t.Errorf("entry method = %d, want Deflate", zr.File[0].Method)
+	}
+	for _, name := range []string{"a.csv", "b.csv"} {
+		r, err := NewZipCSVReader(zr, name)
+		if err != nil {
+			t.Fatalf("NewZipCSVReader(%q) error: %v", name, err)
+		}
+		out, err := r.ReadAll()
+		if err != nil {
+			t.Fatalf("ReadAll() error: %v", err)
+		}
+		if !reflect.DeepEqual(out, records) {
+			t.Errorf("ReadAll() of %s:\ngot  %v\nwant %v", name, out, records)
+		}
+		if err := r.Close(); err != nil {
+			t.Errorf("Close() error: %v", err)
+		}
+	}
+
+	if _, err := NewZipCSVReader(zr, "missing.csv"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("NewZipCSVReader() of missing entry error = %v, want %v", err, fs.ErrNotExist)
+	}
+}